@@ -2,9 +2,7 @@ package cmd
 
 import (
 	"GameDB/internal/crawler"
-	"GameDB/internal/db"
 	"GameDB/internal/log"
-	"GameDB/internal/utils"
 	"encoding/json"
 	"os"
 
@@ -37,6 +35,7 @@ func init() {
 }
 
 func addRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
 	c := []*AddCommandConfig{}
 	if addCmdCfg.Config != "" {
 		data, err := os.ReadFile(addCmdCfg.Config)
@@ -57,20 +56,13 @@ func addRun(cmd *cobra.Command, args []string) {
 			log.Logger.Error("Failed to parse game id", zap.Error(err))
 			continue
 		}
-		info, err := db.GetGameInfoByPlatformID(v.IDtype, v.ID)
-		if err == nil {
-			info.GameIDs = append(info.GameIDs, objID)
-			info.GameIDs = utils.Unique(info.GameIDs)
-			err = db.SaveGameInfo(info)
-			if err != nil {
-				log.Logger.Error("Failed to save game info", zap.Error(err))
-			}
-			log.Logger.Info("Updated game info", zap.String("game_id", v.GameID), zap.String("id_type", v.IDtype), zap.Int("id", v.ID))
-			continue
-		}
-		err = crawler.AddGameInfoManually(objID, v.IDtype, v.ID)
+		err = crawler.AddGameInfoManually(ctx, objID, v.IDtype, v.ID)
 		if err != nil {
 			log.Logger.Error("Failed to add game info", zap.Error(err))
+			continue
+		}
+		if err := crawler.SaveManualMatchOverride(ctx, objID, v.IDtype, v.ID, "added by hand"); err != nil {
+			log.Logger.Warn("Failed to save match override", zap.String("game_id", v.GameID), zap.Error(err))
 		}
 		log.Logger.Info("Added game info", zap.String("game_id", v.GameID), zap.String("id_type", v.IDtype), zap.Int("id", v.ID))
 	}
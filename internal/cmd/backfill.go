@@ -0,0 +1,437 @@
+package cmd
+
+import (
+	"GameDB/internal/crawler"
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"GameDB/internal/utils"
+	"reflect"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var backfillCmd = &cobra.Command{
+	Use:  "backfill",
+	Long: "Fill fields added to existing data",
+}
+
+var backfillMagnetsCmd = &cobra.Command{
+	Use:  "magnets",
+	Long: "Parse info-hash, display name and trackers of every game download and remove duplicate torrents",
+	Run:  backfillMagnetsRun,
+}
+
+var backfillFilesCmd = &cobra.Command{
+	Use:  "files",
+	Long: "List the files of every stored .torrent file, downloads crawled from a magnet only are skipped",
+	Run:  backfillFilesRun,
+}
+
+var backfillSizesCmd = &cobra.Command{
+	Use:  "sizes",
+	Long: "Parse the size in bytes of every game download",
+	Run:  backfillSizesRun,
+}
+
+var backfillReleasesCmd = &cobra.Command{
+	Use:  "releases",
+	Long: "Parse the release attributes of every game download from its raw name",
+	Run:  backfillReleasesRun,
+}
+
+var backfillExternalIDsCmd = &cobra.Command{
+	Use:  "external-ids",
+	Long: "Fill the Steam, GOG and other store IDs of every game info from IGDB",
+	Run:  backfillExternalIDsRun,
+}
+
+var backfillPlaytimesCmd = &cobra.Command{
+	Use:  "playtimes",
+	Long: "Fill the HowLongToBeat playtime of every game info without one",
+	Run:  backfillPlaytimesRun,
+}
+
+var backfillIGDBAttributesCmd = &cobra.Command{
+	Use:  "igdb-attributes",
+	Long: "Fill the genres, themes, game modes, perspectives, franchises, release date and rating of every IGDB game info",
+	Run:  backfillIGDBAttributesRun,
+}
+
+var backfillSteamCmd = &cobra.Command{
+	Use:  "steam",
+	Long: "Fill the Steam store metadata of every game info with a Steam ID",
+	Run:  backfillSteamRun,
+}
+
+const backfillBatchSize = 500
+
+func init() {
+	backfillCmd.AddCommand(backfillMagnetsCmd)
+	backfillCmd.AddCommand(backfillFilesCmd)
+	backfillCmd.AddCommand(backfillSizesCmd)
+	backfillCmd.AddCommand(backfillReleasesCmd)
+	backfillCmd.AddCommand(backfillExternalIDsCmd)
+	backfillCmd.AddCommand(backfillPlaytimesCmd)
+	backfillCmd.AddCommand(backfillIGDBAttributesCmd)
+	backfillCmd.AddCommand(backfillSteamCmd)
+	RootCmd.AddCommand(backfillCmd)
+}
+
+func backfillMagnetsRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	games, err := db.GetAllGameDownloads(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to get games", zap.Error(err))
+		return
+	}
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].CreatedAt.Before(games[j].CreatedAt)
+	})
+	var keep []*model.GameDownload
+	first := map[string]*model.GameDownload{}
+	duplicates := map[primitive.ObjectID][]primitive.ObjectID{}
+	for _, game := range games {
+		db.SetMagnetFields(game)
+		if game.InfoHash == "" {
+			// saved to unset the fields of a magnet that no longer parses
+			keep = append(keep, game)
+			continue
+		}
+		if kept, exist := first[game.InfoHash]; exist {
+			duplicates[kept.ID] = append(duplicates[kept.ID], game.ID)
+			continue
+		}
+		first[game.InfoHash] = game
+		keep = append(keep, game)
+	}
+	removed := 0
+	for id, drop := range duplicates {
+		removed += len(drop)
+		if err := db.RemoveDuplicateGameDownloads(ctx, id, drop); err != nil {
+			log.Logger.Error("Failed to remove duplicates", zap.Error(err))
+			return
+		}
+	}
+	for i := 0; i < len(keep); i += backfillBatchSize {
+		end := min(i+backfillBatchSize, len(keep))
+		if err := db.SaveGameDownloads(ctx, keep[i:end]); err != nil {
+			log.Logger.Error("Failed to save games", zap.Error(err))
+			return
+		}
+	}
+	if err := db.CreateInfoHashIndex(ctx); err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+		return
+	}
+	log.Logger.Info(
+		"Backfilled magnets",
+		zap.Int("total", len(games)),
+		zap.Int("parsed", len(keep)+removed),
+		zap.Int("removed", removed),
+	)
+}
+
+func backfillFilesRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	games, err := db.GetGameDownloadsWithoutFiles(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to get games", zap.Error(err))
+		return
+	}
+	var updated []*model.GameDownload
+	for _, game := range games {
+		if ctx.Err() != nil {
+			break
+		}
+		_, data, err := db.GetTorrentFile(ctx, game.TorrentFileID)
+		if err != nil {
+			log.Logger.Warn("Failed to get torrent file", zap.String("id", game.ID.Hex()), zap.Error(err))
+			continue
+		}
+		files, err := utils.GetTorrentFiles(data)
+		if err != nil {
+			log.Logger.Warn("Failed to list torrent files", zap.String("id", game.ID.Hex()), zap.Error(err))
+			continue
+		}
+		game.Files = files
+		updated = append(updated, game)
+	}
+	for i := 0; i < len(updated); i += backfillBatchSize {
+		end := min(i+backfillBatchSize, len(updated))
+		if err := db.SaveGameDownloads(ctx, updated[i:end]); err != nil {
+			log.Logger.Error("Failed to save games", zap.Error(err))
+			return
+		}
+	}
+	log.Logger.Info("Backfilled files", zap.Int("total", len(games)), zap.Int("updated", len(updated)))
+	// only .torrent files can be read offline, the others are listed when
+	// their source is crawled again
+	skipped, err := db.CountGameDownloadsWithoutTorrentFile(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to count games without torrent file", zap.Error(err))
+		return
+	}
+	if skipped > 0 {
+		log.Logger.Warn("Skipped games without torrent file", zap.Int64("skipped", skipped))
+	}
+}
+
+func backfillSizesRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	games, err := db.GetAllGameDownloads(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to get games", zap.Error(err))
+		return
+	}
+	var updated []*model.GameDownload
+	for _, game := range games {
+		before := game.SizeBytes
+		db.SetSizeBytes(game)
+		if game.SizeBytes == 0 {
+			log.Logger.Warn("Failed to parse size", zap.String("id", game.ID.Hex()), zap.String("size", game.Size))
+			continue
+		}
+		if game.SizeBytes != before {
+			updated = append(updated, game)
+		}
+	}
+	for i := 0; i < len(updated); i += backfillBatchSize {
+		end := min(i+backfillBatchSize, len(updated))
+		if err := db.SaveGameDownloads(ctx, updated[i:end]); err != nil {
+			log.Logger.Error("Failed to save games", zap.Error(err))
+			return
+		}
+	}
+	log.Logger.Info("Backfilled sizes", zap.Int("total", len(games)), zap.Int("updated", len(updated)))
+}
+
+func backfillReleasesRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	games, err := db.GetAllGameDownloads(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to get games", zap.Error(err))
+		return
+	}
+	parsed := 0
+	for i := 0; i < len(games); i += backfillBatchSize {
+		end := min(i+backfillBatchSize, len(games))
+		// SaveGameDownloads parses the release of every game download
+		if err := db.SaveGameDownloads(ctx, games[i:end]); err != nil {
+			log.Logger.Error("Failed to save games", zap.Error(err))
+			return
+		}
+		for _, game := range games[i:end] {
+			if game.Release != nil {
+				parsed++
+			}
+		}
+	}
+	log.Logger.Info("Backfilled releases", zap.Int("total", len(games)), zap.Int("parsed", parsed))
+}
+
+func backfillExternalIDsRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	infos, err := db.GetAllGameInfos(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to get game infos", zap.Error(err))
+		return
+	}
+	updated := 0
+	for _, info := range infos {
+		if ctx.Err() != nil {
+			break
+		}
+		changed, err := crawler.SetIGDBExternalIDs(ctx, info)
+		if err != nil {
+			log.Logger.Warn("Failed to get igdb external games", zap.String("id", info.ID.Hex()), zap.Int("igdb_id", info.IGDBID), zap.Error(err))
+			continue
+		}
+		if !changed {
+			continue
+		}
+		// IDs are only ever added, the ones still missing stay unset
+		var fields bson.D
+		if info.SteamID != 0 {
+			fields = append(fields, bson.E{Key: "steam_id", Value: info.SteamID})
+		}
+		if info.GOGID != 0 {
+			fields = append(fields, bson.E{Key: "gog_id", Value: info.GOGID})
+		}
+		if len(info.StoreIDs) > 0 {
+			fields = append(fields, bson.E{Key: "store_ids", Value: info.StoreIDs})
+		}
+		if err := db.UpdateGameInfoFields(ctx, info.ID, fields); err != nil {
+			log.Logger.Error("Failed to save game info", zap.String("id", info.ID.Hex()), zap.Error(err))
+			return
+		}
+		updated++
+	}
+	log.Logger.Info("Backfilled external ids", zap.Int("total", len(infos)), zap.Int("updated", updated))
+}
+
+func backfillPlaytimesRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	infos, err := db.GetAllGameInfos(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to get game infos", zap.Error(err))
+		return
+	}
+	updated := 0
+	for _, info := range infos {
+		if ctx.Err() != nil {
+			break
+		}
+		if info.Playtime != nil {
+			continue
+		}
+		if err := crawler.SetHowLongToBeat(ctx, info); err != nil {
+			log.Logger.Warn("Failed to get playtime", zap.String("id", info.ID.Hex()), zap.String("name", info.Name), zap.Error(err))
+			continue
+		}
+		if info.Playtime == nil {
+			continue
+		}
+		fields := bson.D{
+			{Key: "playtime", Value: info.Playtime},
+			{Key: "how_long_to_beat_id", Value: info.HowLongToBeatID},
+		}
+		if err := db.UpdateGameInfoFields(ctx, info.ID, fields); err != nil {
+			log.Logger.Error("Failed to save game info", zap.String("id", info.ID.Hex()), zap.Error(err))
+			return
+		}
+		updated++
+	}
+	log.Logger.Info("Backfilled playtimes", zap.Int("total", len(infos)), zap.Int("updated", updated))
+}
+
+func backfillIGDBAttributesRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	infos, err := db.GetAllGameInfos(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to get game infos", zap.Error(err))
+		return
+	}
+	updated := 0
+	for _, info := range infos {
+		if ctx.Err() != nil {
+			break
+		}
+		if info.IGDBID == 0 {
+			continue
+		}
+		detail, err := crawler.GetIGDBAppDetailCache(ctx, info.IGDBID)
+		if err != nil {
+			log.Logger.Warn("Failed to get igdb detail", zap.String("id", info.ID.Hex()), zap.Int("igdb_id", info.IGDBID), zap.Error(err))
+			continue
+		}
+		before := igdbAttributeFields(info)
+		crawler.SetIGDBAttributes(ctx, info, detail)
+		fields := igdbAttributeFields(info)
+		if sameFields(before, fields) {
+			continue
+		}
+		if err := db.UpdateGameInfoFields(ctx, info.ID, fields); err != nil {
+			log.Logger.Error("Failed to save game info", zap.String("id", info.ID.Hex()), zap.Error(err))
+			return
+		}
+		updated++
+	}
+	log.Logger.Info("Backfilled igdb attributes", zap.Int("total", len(infos)), zap.Int("updated", updated))
+}
+
+func backfillSteamRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	infos, err := db.GetAllGameInfos(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to get game infos", zap.Error(err))
+		return
+	}
+	updated := 0
+	for _, info := range infos {
+		if ctx.Err() != nil {
+			break
+		}
+		if info.SteamID == 0 {
+			continue
+		}
+		detail, err := crawler.GetSteamAppDetailCache(ctx, info.SteamID)
+		if err != nil {
+			log.Logger.Warn("Failed to get steam app detail", zap.String("id", info.ID.Hex()), zap.Int("steam_id", info.SteamID), zap.Error(err))
+			continue
+		}
+		before := bson.D{{Key: "steam", Value: info.Steam}}
+		hadReleaseDate := info.ReleaseDate != nil
+		crawler.SetSteamAttributes(info, detail)
+		fields := bson.D{{Key: "steam", Value: info.Steam}}
+		// the release date of IGDB is kept, Steam only fills a missing one
+		if !hadReleaseDate && info.ReleaseDate != nil {
+			fields = append(fields, bson.E{Key: "release_date", Value: info.ReleaseDate})
+		} else if sameFields(before, fields) {
+			continue
+		}
+		if err := db.UpdateGameInfoFields(ctx, info.ID, fields); err != nil {
+			log.Logger.Error("Failed to save game info", zap.String("id", info.ID.Hex()), zap.Error(err))
+			return
+		}
+		updated++
+	}
+	log.Logger.Info("Backfilled steam metadata", zap.Int("total", len(infos)), zap.Int("updated", updated))
+}
+
+// igdbAttributeFields returns the fields SetIGDBAttributes fills that have a
+// value.
+func igdbAttributeFields(info *model.GameInfo) bson.D {
+	fields := bson.D{}
+	for _, list := range []struct {
+		key    string
+		values []string
+	}{
+		{"genres", info.Genres},
+		{"themes", info.Themes},
+		{"game_modes", info.GameModes},
+		{"player_perspectives", info.Perspectives},
+		{"franchises", info.Franchises},
+	} {
+		if len(list.values) > 0 {
+			fields = append(fields, bson.E{Key: list.key, Value: list.values})
+		}
+	}
+	if info.ReleaseDate != nil {
+		fields = append(fields, bson.E{Key: "release_date", Value: info.ReleaseDate})
+	}
+	if info.Rating != 0 {
+		fields = append(fields, bson.E{Key: "rating", Value: info.Rating})
+	}
+	if info.RatingCount != 0 {
+		fields = append(fields, bson.E{Key: "rating_count", Value: info.RatingCount})
+	}
+	return fields
+}
+
+// sameFields reports whether two sets of fields would be stored the same,
+// e.g. dates are compared in milliseconds like MongoDB keeps them.
+func sameFields(a bson.D, b bson.D) bool {
+	decode := func(fields bson.D) (bson.M, error) {
+		data, err := bson.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		var doc bson.M
+		err = bson.Unmarshal(data, &doc)
+		return doc, err
+	}
+	x, err := decode(a)
+	if err != nil {
+		return false
+	}
+	y, err := decode(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
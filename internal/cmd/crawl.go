@@ -5,7 +5,9 @@ import (
 	"GameDB/internal/log"
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
-	"slices"
+	"context"
+	"errors"
+	"math"
 	"strconv"
 	"strings"
 
@@ -23,32 +25,45 @@ type CrawlCommandConfig struct {
 	Source string
 	Page   string
 	All    bool
+	Resume bool
+	Feed   bool
 	Num    int
 }
 
 var crawlCmdCfg CrawlCommandConfig
 
 func init() {
-	crawlCmd.Flags().StringVarP(&crawlCmdCfg.Source, "source", "s", "", "source to crawl (fitgirl/dodi/kaoskrew/freegog/xatab/onlinefix)")
-	crawlCmd.Flags().StringVarP(&crawlCmdCfg.Page, "pages", "p", "1", "pages to crawl (1,2,3 or 1-3), only available for fitgirl/dodi/kaoskrew/xatab/onlinefix")
+	crawlCmd.Flags().StringVarP(&crawlCmdCfg.Source, "source", "s", "", sourceUsage("source to crawl"))
+	crawlCmd.Flags().StringVarP(&crawlCmdCfg.Page, "pages", "p", "1", "pages to crawl (1,2,3 or 1-3), only available for paged sources")
 	crawlCmd.Flags().BoolVarP(&crawlCmdCfg.All, "all", "a", false, "crawl all page, ignore pages")
-	crawlCmd.Flags().IntVarP(&crawlCmdCfg.Num, "num", "n", 1, "number of items to crawl, only available for freegog")
+	crawlCmd.Flags().BoolVarP(&crawlCmdCfg.Resume, "resume", "r", false, "resume the last full crawl from its checkpoint, implies --all")
+	crawlCmd.Flags().BoolVarP(&crawlCmdCfg.Feed, "feed", "f", false, "crawl new items from the source's RSS/Atom feed")
+	crawlCmd.Flags().IntVarP(&crawlCmdCfg.Num, "num", "n", 1, "number of items to crawl, only available for list sources")
 	RootCmd.AddCommand(crawlCmd)
 }
 
 func crawlRun(cmd *cobra.Command, args []string) {
-	crawlCmdCfg.Source = strings.ToLower(crawlCmdCfg.Source)
-	if slices.Contains([]string{"fitgirl", "dodi", "kaoskrew"}, crawlCmdCfg.Source) {
-		crawl1337x()
-	} else if crawlCmdCfg.Source == "freegog" {
-		crawlFreeGOG()
-	} else if crawlCmdCfg.Source == "xatab" {
-		crawlXatab()
-	} else if crawlCmdCfg.Source == "onlinefix" {
-		crwalOnlineFix()
-	} else {
+	src, ok := crawler.GetSource(crawlCmdCfg.Source)
+	if !ok {
 		log.Logger.Error("Invalid source", zap.String("source", crawlCmdCfg.Source))
+		return
+	}
+	ctx, run := crawler.StartRun(cmd.Context(), model.CrawlRunTypeCrawl, src.Name())
+	var err error
+	if crawlCmdCfg.Feed {
+		err = crawlFeed(ctx, src)
+	} else {
+		switch s := src.(type) {
+		case crawler.PagedSource:
+			err = crawlPaged(ctx, s)
+		case crawler.ListSource:
+			err = crawlList(ctx, s)
+		default:
+			err = errors.New("source can not be crawled")
+			log.Logger.Error("Source can not be crawled", zap.String("source", src.Name()))
+		}
 	}
+	run.Finish(ctx, err)
 }
 
 func pagination(pageStr string) ([]int, error) {
@@ -83,89 +98,39 @@ func pagination(pageStr string) ([]int, error) {
 	return utils.Unique(pages), nil
 }
 
-func crawl1337x() {
-	var crawl1337xMulti func(pages []int) ([]*model.GameDownload, error)
-	var crawl1337xAll func() ([]*model.GameDownload, error)
-	switch crawlCmdCfg.Source {
-	case "fitgirl":
-		crawl1337xMulti = crawler.CrawlFitgirlMulti
-		crawl1337xAll = crawler.CrawlFitgirlAll
-	case "dodi":
-		crawl1337xMulti = crawler.CrawlDODIMulti
-		crawl1337xAll = crawler.CrawlDODIAll
-	case "kaoskrew":
-		crawl1337xMulti = crawler.CrawlKaOsKrewMulti
-		crawl1337xAll = crawler.CrawlKaOsKrewAll
+func crawlPaged(ctx context.Context, src crawler.PagedSource) error {
+	if crawlCmdCfg.All || crawlCmdCfg.Resume {
+		_, err := crawler.CrawlAll(ctx, src, crawlCmdCfg.Resume)
+		return err
 	}
-
-	if crawlCmdCfg.All {
-		_, err := crawl1337xAll()
-		if err != nil {
-			return
-		}
-	} else {
-		pages, err := pagination(crawlCmdCfg.Page)
-		if err != nil {
-			log.Logger.Error("Invalid page", zap.String("page", crawlCmdCfg.Page))
-			return
-		}
-		_, err = crawl1337xMulti(pages)
-		if err != nil {
-			return
-		}
+	pages, err := pagination(crawlCmdCfg.Page)
+	if err != nil {
+		log.Logger.Error("Invalid page", zap.String("page", crawlCmdCfg.Page))
+		return err
 	}
+	_, err = crawler.CrawlMulti(ctx, src, pages)
+	return err
 }
 
-func crawlFreeGOG() {
+func crawlList(ctx context.Context, src crawler.ListSource) error {
 	if crawlCmdCfg.Num <= 0 {
 		log.Logger.Error("Invalid num", zap.Int("num", crawlCmdCfg.Num))
-		return
-	}
-	var err error
-	if crawlCmdCfg.All {
-		_, err = crawler.CrawlFreeGOGAll()
-	} else {
-		_, err = crawler.CrawlFreeGOG(crawlCmdCfg.Num)
-	}
-	if err != nil {
-		return
+		return errors.New("invalid num")
 	}
-}
-
-func crawlXatab() {
+	num := crawlCmdCfg.Num
 	if crawlCmdCfg.All {
-		_, err := crawler.CrawlXatabAll()
-		if err != nil {
-			return
-		}
-	} else {
-		pages, err := pagination(crawlCmdCfg.Page)
-		if err != nil {
-			log.Logger.Error("Invalid page", zap.String("page", crawlCmdCfg.Page))
-			return
-		}
-		_, err = crawler.CrawlXatabMulti(pages)
-		if err != nil {
-			return
-		}
+		num = math.MaxInt
 	}
+	_, err := crawler.CrawlList(ctx, src, num)
+	return err
 }
 
-func crwalOnlineFix() {
-	if crawlCmdCfg.All {
-		_, err := crawler.CrawlOnlineFixAll()
-		if err != nil {
-			return
-		}
-	} else {
-		pages, err := pagination(crawlCmdCfg.Page)
-		if err != nil {
-			log.Logger.Error("Invalid page", zap.String("page", crawlCmdCfg.Page))
-			return
-		}
-		_, err = crawler.CrawlOnlineFixMulti(pages)
-		if err != nil {
-			return
-		}
+func crawlFeed(ctx context.Context, src crawler.Source) error {
+	s, ok := src.(crawler.FeedSource)
+	if !ok || s.FeedURL() == "" {
+		log.Logger.Error("Source has no feed", zap.String("source", src.Name()))
+		return errors.New("source has no feed")
 	}
+	_, err := crawler.CrawlFeed(ctx, s)
+	return err
 }
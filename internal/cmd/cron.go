@@ -13,14 +13,16 @@ var cronCmd = &cobra.Command{
 	Use:  "cron",
 	Long: "Execute scheduled tasks to crawl games",
 	Run: func(cmd *cobra.Command, args []string) {
-		task.Crawl()
+		ctx := cmd.Context()
+		task.Crawl(ctx)
 		c := cron.New()
-		_, err := c.AddFunc("0 0 * * *", task.Crawl)
+		_, err := c.AddFunc("0 0 * * *", func() { task.Crawl(ctx) })
 		if err != nil {
 			log.Logger.Error("Error adding cron job", zap.Error(err))
 		}
 		c.Start()
-		select {}
+		<-ctx.Done()
+		<-c.Stop().Done()
 	},
 }
 
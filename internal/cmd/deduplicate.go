@@ -12,7 +12,7 @@ var deduplicateCmd = &cobra.Command{
 	Use:  "deduplicate",
 	Long: "Remove duplicate games caused by incorrect crawling",
 	Run: func(cmd *cobra.Command, args []string) {
-		err := db.DeduplicateGames()
+		err := db.DeduplicateGames(cmd.Context())
 		if err != nil {
 			log.Logger.Error("Failed to deduplicate games", zap.Error(err))
 		}
@@ -4,9 +4,13 @@ import (
 	"GameDB/internal/crawler"
 	"GameDB/internal/db"
 	"GameDB/internal/log"
-	"strings"
+	"GameDB/internal/model"
+	"GameDB/internal/task"
+	"context"
+	"fmt"
 
 	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
 
@@ -17,120 +21,102 @@ var formatCmd = &cobra.Command{
 }
 
 type FormatCommandConfig struct {
-	Source string
+	Source  string
+	DryRun  bool
+	Diff    bool
+	Rematch bool
 }
 
 var formatCmdCfg FormatCommandConfig
 
 func init() {
-	formatCmd.Flags().StringVarP(&formatCmdCfg.Source, "source", "s", "", "source to fix (fitgirl/dodi/kaoskrew/freegog/xatab/onlinefix)")
+	formatCmd.Flags().StringVarP(&formatCmdCfg.Source, "source", "s", "", sourceUsage("source to fix"))
+	formatCmd.Flags().BoolVarP(&formatCmdCfg.DryRun, "dry-run", "n", false, "only report the names that would change")
+	formatCmd.Flags().BoolVarP(&formatCmdCfg.Diff, "diff", "d", false, "print the old and new name of every changed game")
+	formatCmd.Flags().BoolVarP(&formatCmdCfg.Rematch, "rematch", "m", false, "match the games whose names changed again, except reviewed, overridden and manually added ones")
 	RootCmd.AddCommand(formatCmd)
 }
 
 func formatRun(cmd *cobra.Command, args []string) {
-	formatCmdCfg.Source = strings.ToLower(formatCmdCfg.Source)
-	switch formatCmdCfg.Source {
-	case "fitgirl":
-		items, err := db.GetFitgirlAllGameDownloads()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
-		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.FitgirlFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameDownload(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
-		}
-	case "dodi":
-		items, err := db.GetDODIAllGameDownloads()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
-		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.DODIFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameDownload(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
-		}
-	case "kaoskrew":
-		items, err := db.GetKaOsKrewAllGameDownloads()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
-		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.KaOsKrewFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameDownload(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
+	ctx := cmd.Context()
+	src, ok := crawler.GetSource(formatCmdCfg.Source)
+	if !ok {
+		log.Logger.Error("Invalid source", zap.String("source", formatCmdCfg.Source))
+		return
+	}
+	items, err := db.GetAllGameDownloadsWithAuthor(ctx, src.Author())
+	if err != nil {
+		log.Logger.Error("Failed to get games", zap.Error(err))
+		return
+	}
+	formatter := crawler.SourceFormatter(src)
+	var changed []*model.GameDownload
+	for _, item := range items {
+		oldName := item.Name
+		item.Name = formatter(item.RawName)
+		if oldName == item.Name {
+			continue
 		}
-	case "freegog":
-		items, err := db.GetAllFreeGOGGameDownloads()
-		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
+		changed = append(changed, item)
+		if formatCmdCfg.Diff {
+			fmt.Printf("%s %s\n- %s\n+ %s\n\n", item.ID.Hex(), item.RawName, oldName, item.Name)
 		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.FreeGOGFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameDownload(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
+		if formatCmdCfg.DryRun {
+			continue
 		}
-	case "xatab":
-		items, err := db.GetXatabGameDownloads()
+		log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
+		err := db.SaveGameDownload(ctx, item)
 		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
+			log.Logger.Error("Failed to update item", zap.Error(err))
 		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.XatabFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameDownload(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
-		}
-	case "onlinefix":
-		items, err := db.GetOnlineFixGameDownloads()
+	}
+	fmt.Printf("%s: %d of %d names changed\n", src.Name(), len(changed), len(items))
+	if formatCmdCfg.DryRun || !formatCmdCfg.Rematch || len(changed) == 0 {
+		return
+	}
+	overrides, err := crawler.LoadMatchOverrides(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to load match overrides", zap.Error(err))
+		return
+	}
+	var rematch []*model.GameDownload
+	ids := make([]primitive.ObjectID, 0, len(changed))
+	for _, item := range changed {
+		ok, err := rematchable(ctx, item, overrides)
 		if err != nil {
-			log.Logger.Error("Failed to get games", zap.Error(err))
-			return
+			log.Logger.Error("Failed to check game", zap.String("id", item.ID.Hex()), zap.Error(err))
+			continue
 		}
-		for _, item := range items {
-			oldName := item.Name
-			item.Name = crawler.OnlineFixFormatter(item.RawName)
-			if oldName != item.Name {
-				log.Logger.Info("Fix name", zap.String("old", oldName), zap.String("raw", item.RawName), zap.String("name", item.Name))
-				err := db.SaveGameDownload(item)
-				if err != nil {
-					log.Logger.Error("Failed to update item", zap.Error(err))
-				}
-			}
+		if !ok {
+			continue
 		}
+		rematch = append(rematch, item)
+		ids = append(ids, item.ID)
+	}
+	fmt.Printf("%s: %d of %d changed games are rematched, the others were reviewed, overridden or added by hand\n", src.Name(), len(rematch), len(changed))
+	if len(rematch) == 0 {
+		return
+	}
+	if err := db.UnlinkGameDownloads(ctx, ids, primitive.NilObjectID); err != nil {
+		log.Logger.Error("Failed to unlink games", zap.Error(err))
+		return
+	}
+	ctx, run := crawler.StartRun(ctx, model.CrawlRunTypeOrganize, src.Name())
+	run.Found(len(rematch))
+	task.Organize(ctx, rematch)
+	run.Finish(ctx, ctx.Err())
+}
+
+// rematchable reports whether a game download is unlinked or linked by
+// organize. Downloads that were reviewed, have a match override or were
+// added by hand keep their links.
+func rematchable(ctx context.Context, item *model.GameDownload, overrides *crawler.MatchOverrides) (bool, error) {
+	if item.Review != nil || overrides.Find(item) != nil {
+		return false, nil
+	}
+	if item.Match != nil {
+		return true, nil
 	}
+	linked, err := db.IsGameDownloadLinked(ctx, item.ID)
+	return !linked, err
 }
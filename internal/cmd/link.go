@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"GameDB/internal/crawler"
+	"GameDB/internal/log"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var unlinkCmd = &cobra.Command{
+	Use:  "unlink <game-id>",
+	Long: "Remove a game from a game info, or from all its game infos, and match it again if it is left in none",
+	Args: cobra.ExactArgs(1),
+	Run:  unlinkRun,
+}
+
+var moveCmd = &cobra.Command{
+	Use:  "move <game-id> <game-info-id>",
+	Long: "Move a game from a game info, or from all its game infos, to another game info",
+	Args: cobra.ExactArgs(2),
+	Run:  moveRun,
+}
+
+type linkCommandConfig struct {
+	From string
+}
+
+var linkCmdCfg linkCommandConfig
+
+func init() {
+	unlinkCmd.Flags().StringVarP(&linkCmdCfg.From, "from", "f", "", "game info id to remove the game from (default all)")
+	moveCmd.Flags().StringVarP(&linkCmdCfg.From, "from", "f", "", "game info id to move the game from (default all)")
+	RootCmd.AddCommand(unlinkCmd)
+	RootCmd.AddCommand(moveCmd)
+}
+
+func unlinkRun(cmd *cobra.Command, args []string) {
+	gameID, from, ok := parseLinkIDs(args[0])
+	if !ok {
+		return
+	}
+	orphaned, err := crawler.UnlinkGameDownload(cmd.Context(), gameID, from)
+	if err != nil {
+		log.Logger.Error("Failed to unlink game", zap.Error(err))
+		return
+	}
+	log.Logger.Info("Unlinked game", zap.String("game_id", args[0]), zap.Bool("orphaned", orphaned))
+}
+
+func moveRun(cmd *cobra.Command, args []string) {
+	gameID, from, ok := parseLinkIDs(args[0])
+	if !ok {
+		return
+	}
+	to, err := primitive.ObjectIDFromHex(args[1])
+	if err != nil {
+		log.Logger.Error("Failed to parse game info id", zap.Error(err))
+		return
+	}
+	if err := crawler.MoveGameDownload(cmd.Context(), gameID, from, to); err != nil {
+		log.Logger.Error("Failed to move game", zap.Error(err))
+		return
+	}
+	log.Logger.Info("Moved game", zap.String("game_id", args[0]), zap.String("game_info_id", args[1]))
+}
+
+func parseLinkIDs(game string) (primitive.ObjectID, primitive.ObjectID, bool) {
+	gameID, err := primitive.ObjectIDFromHex(game)
+	if err != nil {
+		log.Logger.Error("Failed to parse game id", zap.Error(err))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	if linkCmdCfg.From == "" {
+		return gameID, primitive.NilObjectID, true
+	}
+	from, err := primitive.ObjectIDFromHex(linkCmdCfg.From)
+	if err != nil {
+		log.Logger.Error("Failed to parse game info id", zap.Error(err))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return gameID, from, true
+}
@@ -26,8 +26,9 @@ func init() {
 }
 
 func listRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
 	if listCmdCfg.Unid {
-		games, err := db.GetGameDownloadsNotInGameInfos(-1)
+		games, err := db.GetGameDownloadsNotInGameInfos(ctx, -1)
 		if err != nil {
 			log.Logger.Error("Failed to get games", zap.Error(err))
 		}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"GameDB/internal/crawler"
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"errors"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:  "merge <keep> <drop...>",
+	Long: "Merge duplicate game infos into one, or list and merge the duplicates found with --detect",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if mergeCmdCfg.Detect {
+			if mergeCmdCfg.Force {
+				return errors.New("--force cannot be used with --detect")
+			}
+			return cobra.NoArgs(cmd, args)
+		}
+		if mergeCmdCfg.Apply {
+			return errors.New("--apply requires --detect")
+		}
+		return cobra.MinimumNArgs(2)(cmd, args)
+	},
+	Run: mergeRun,
+}
+
+type mergeCommandConfig struct {
+	Detect bool
+	Apply  bool
+	Force  bool
+}
+
+var mergeCmdCfg mergeCommandConfig
+
+func init() {
+	mergeCmd.Flags().BoolVarP(&mergeCmdCfg.Detect, "detect", "d", false, "list game infos sharing an ID, a name or an alias")
+	mergeCmd.Flags().BoolVarP(&mergeCmdCfg.Apply, "apply", "a", false, "merge the detected duplicates")
+	mergeCmd.Flags().BoolVarP(&mergeCmdCfg.Force, "force", "f", false, "merge game infos with different IDs, keeping the IDs of the kept one")
+	RootCmd.AddCommand(mergeCmd)
+}
+
+func mergeRun(cmd *cobra.Command, args []string) {
+	if mergeCmdCfg.Detect {
+		mergeDetected(cmd)
+		return
+	}
+	ids := make([]primitive.ObjectID, 0, len(args))
+	for _, arg := range args {
+		id, err := primitive.ObjectIDFromHex(arg)
+		if err != nil {
+			log.Logger.Error("Failed to parse game info id", zap.String("id", arg), zap.Error(err))
+			return
+		}
+		ids = append(ids, id)
+	}
+	info, err := crawler.MergeGameInfos(cmd.Context(), ids[0], ids[1:], mergeCmdCfg.Force)
+	if err != nil {
+		log.Logger.Error("Failed to merge game infos", zap.Error(err))
+		return
+	}
+	log.Logger.Info("Merged game infos", zap.String("id", info.ID.Hex()), zap.String("name", info.Name), zap.Int("games", len(info.GameIDs)))
+}
+
+func mergeDetected(cmd *cobra.Command) {
+	infos, err := db.GetAllGameInfos(cmd.Context())
+	if err != nil {
+		log.Logger.Error("Failed to get game infos", zap.Error(err))
+		return
+	}
+	groups := crawler.FindDuplicateGameInfos(infos)
+	merged := 0
+	for _, group := range groups {
+		keep := group.Infos[0]
+		log.Logger.Info("Duplicate", zap.String("keep", keep.ID.Hex()), zap.String("name", keep.Name), zap.Bool("certain", group.Certain))
+		drop := make([]primitive.ObjectID, 0, len(group.Infos)-1)
+		for _, info := range group.Infos[1:] {
+			log.Logger.Info("Drop", zap.String("id", info.ID.Hex()), zap.String("name", info.Name))
+			drop = append(drop, info.ID)
+		}
+		// groups sharing only a name without a known release year for
+		// both are left to be merged by hand
+		if !mergeCmdCfg.Apply || !group.Certain {
+			continue
+		}
+		if _, err := crawler.MergeGameInfos(cmd.Context(), keep.ID, drop, false); err != nil {
+			log.Logger.Error("Failed to merge game infos", zap.String("keep", keep.ID.Hex()), zap.Error(err))
+			continue
+		}
+		merged++
+	}
+	log.Logger.Info("Detected duplicates", zap.Int("groups", len(groups)), zap.Int("merged", merged))
+}
@@ -4,6 +4,8 @@ import (
 	"GameDB/internal/crawler"
 	"GameDB/internal/db"
 	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"GameDB/internal/task"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -27,35 +29,14 @@ func init() {
 }
 
 func organizeRun(cmd *cobra.Command, args []string) {
-	games, err := db.GetGameDownloadsNotInGameInfos(organizeCmdCfg.Num)
+	ctx, run := crawler.StartRun(cmd.Context(), model.CrawlRunTypeOrganize, "")
+	games, err := db.GetGameDownloadsNotInGameInfos(ctx, organizeCmdCfg.Num)
 	if err != nil {
 		log.Logger.Error("Failed to get games", zap.Error(err))
+		run.Finish(ctx, err)
+		return
 	}
-	for _, game := range games {
-		gameInfo, err := crawler.ProcessGameWithIGDB(game)
-		if err == nil {
-			err = db.SaveGameInfo(gameInfo)
-			if err != nil {
-				log.Logger.Error("Failed to save game info", zap.Error(err))
-			}
-			continue
-		}
-		gameInfo, err = crawler.ProcessGameWithSteam(game)
-		if err == nil {
-			err = db.SaveGameInfo(gameInfo)
-			if err != nil {
-				log.Logger.Error("Failed to save game info", zap.Error(err))
-			}
-			continue
-		}
-		gameInfo, err = crawler.ProcessGameWithGOG(game)
-		if err == nil {
-			err = db.SaveGameInfo(gameInfo)
-			if err != nil {
-				log.Logger.Error("Failed to save game info", zap.Error(err))
-			}
-			continue
-		}
-		log.Logger.Error("Failed to process game", zap.Error(err))
-	}
+	run.Found(len(games))
+	task.Organize(ctx, games)
+	run.Finish(ctx, ctx.Err())
 }
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"GameDB/internal/crawler"
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"GameDB/internal/task"
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var overrideCmd = &cobra.Command{
+	Use:  "override",
+	Long: "Manage match overrides that map game downloads to a game or ignore them",
+}
+
+var overrideAddCmd = &cobra.Command{
+	Use:  "add",
+	Long: "Add or replace the match override of a download URL or a raw name pattern",
+	Run:  overrideAddRun,
+}
+
+var overrideListCmd = &cobra.Command{
+	Use:  "list",
+	Long: "List match overrides",
+	Run:  overrideListRun,
+}
+
+var overrideRemoveCmd = &cobra.Command{
+	Use:  "remove <override-id>",
+	Long: "Remove a match override",
+	Args: cobra.ExactArgs(1),
+	Run:  overrideRemoveRun,
+}
+
+var overrideExportCmd = &cobra.Command{
+	Use:  "export",
+	Long: "Export match overrides as JSON",
+	Run:  overrideExportRun,
+}
+
+var overrideImportCmd = &cobra.Command{
+	Use:  "import",
+	Long: "Import match overrides exported as JSON",
+	Run:  overrideImportRun,
+}
+
+type overrideCommandConfig struct {
+	URL     string
+	Pattern string
+	IDType  string
+	ID      int
+	Note    string
+	Apply   bool
+	File    string
+}
+
+var overrideCmdCfg overrideCommandConfig
+
+func init() {
+	overrideAddCmd.Flags().StringVarP(&overrideCmdCfg.URL, "url", "u", "", "download page url")
+	overrideAddCmd.Flags().StringVarP(&overrideCmdCfg.Pattern, "pattern", "r", "", "regular expression matched against raw names")
+	overrideAddCmd.Flags().StringVarP(&overrideCmdCfg.IDType, "type", "t", "", "id type (igdb/steam/gog/ignore)")
+	overrideAddCmd.Flags().IntVarP(&overrideCmdCfg.ID, "platform-id", "p", 0, "platform id")
+	overrideAddCmd.Flags().StringVarP(&overrideCmdCfg.Note, "note", "n", "", "why the override exists")
+	overrideAddCmd.Flags().BoolVarP(&overrideCmdCfg.Apply, "apply", "a", false, "match the existing game downloads of the override again")
+	overrideExportCmd.Flags().StringVarP(&overrideCmdCfg.File, "file", "f", "", "output file (default stdout)")
+	overrideImportCmd.Flags().StringVarP(&overrideCmdCfg.File, "file", "f", "", "input file")
+	overrideImportCmd.Flags().BoolVarP(&overrideCmdCfg.Apply, "apply", "a", false, "match the existing game downloads of every override again")
+	_ = overrideImportCmd.MarkFlagRequired("file")
+	overrideCmd.AddCommand(overrideAddCmd)
+	overrideCmd.AddCommand(overrideListCmd)
+	overrideCmd.AddCommand(overrideRemoveCmd)
+	overrideCmd.AddCommand(overrideExportCmd)
+	overrideCmd.AddCommand(overrideImportCmd)
+	RootCmd.AddCommand(overrideCmd)
+}
+
+func overrideAddRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	override := &model.MatchOverride{
+		URL:        overrideCmdCfg.URL,
+		Pattern:    overrideCmdCfg.Pattern,
+		IDType:     overrideCmdCfg.IDType,
+		PlatformID: overrideCmdCfg.ID,
+		Note:       overrideCmdCfg.Note,
+	}
+	if err := saveMatchOverride(ctx, override); err != nil {
+		return
+	}
+	if overrideCmdCfg.Apply {
+		applyMatchOverrides(ctx, []*model.MatchOverride{override})
+	}
+}
+
+func overrideListRun(cmd *cobra.Command, args []string) {
+	overrides, err := db.GetMatchOverrides(cmd.Context())
+	if err != nil {
+		log.Logger.Error("Failed to get match overrides", zap.Error(err))
+		return
+	}
+	for _, override := range overrides {
+		log.Logger.Info(
+			"Override",
+			zap.String("id", override.ID.Hex()),
+			zap.String("url", override.URL),
+			zap.String("pattern", override.Pattern),
+			zap.String("id_type", override.IDType),
+			zap.Int("platform_id", override.PlatformID),
+			zap.String("note", override.Note),
+		)
+	}
+}
+
+func overrideRemoveRun(cmd *cobra.Command, args []string) {
+	id, err := primitive.ObjectIDFromHex(args[0])
+	if err != nil {
+		log.Logger.Error("Failed to parse override id", zap.Error(err))
+		return
+	}
+	removed, err := db.DeleteMatchOverride(cmd.Context(), id)
+	if err != nil {
+		log.Logger.Error("Failed to remove match override", zap.Error(err))
+		return
+	}
+	if !removed {
+		log.Logger.Warn("Match override not found", zap.String("id", args[0]))
+		return
+	}
+	log.Logger.Info("Removed match override", zap.String("id", args[0]))
+}
+
+func overrideExportRun(cmd *cobra.Command, args []string) {
+	overrides, err := db.GetMatchOverrides(cmd.Context())
+	if err != nil {
+		log.Logger.Error("Failed to get match overrides", zap.Error(err))
+		return
+	}
+	data, err := json.MarshalIndent(overrides, "", "  ")
+	if err != nil {
+		log.Logger.Error("Failed to marshal match overrides", zap.Error(err))
+		return
+	}
+	data = append(data, '\n')
+	if overrideCmdCfg.File == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(overrideCmdCfg.File, data, 0644); err != nil {
+		log.Logger.Error("Failed to write file", zap.Error(err))
+		return
+	}
+	log.Logger.Info("Exported match overrides", zap.Int("count", len(overrides)), zap.String("file", overrideCmdCfg.File))
+}
+
+func overrideImportRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	data, err := os.ReadFile(overrideCmdCfg.File)
+	if err != nil {
+		log.Logger.Error("Failed to read file", zap.Error(err))
+		return
+	}
+	var overrides []*model.MatchOverride
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		log.Logger.Error("Failed to unmarshal file", zap.Error(err))
+		return
+	}
+	var imported []*model.MatchOverride
+	for _, override := range overrides {
+		if err := saveMatchOverride(ctx, override); err == nil {
+			imported = append(imported, override)
+		}
+	}
+	log.Logger.Info("Imported match overrides", zap.Int("total", len(overrides)), zap.Int("imported", len(imported)))
+	if overrideCmdCfg.Apply {
+		applyMatchOverrides(ctx, imported)
+	}
+}
+
+func saveMatchOverride(ctx context.Context, override *model.MatchOverride) error {
+	if err := crawler.ValidateMatchOverride(override); err != nil {
+		log.Logger.Error("Invalid match override", zap.String("url", override.URL), zap.String("pattern", override.Pattern), zap.Error(err))
+		return err
+	}
+	if err := db.SaveMatchOverride(ctx, override); err != nil {
+		log.Logger.Error("Failed to save match override", zap.Error(err))
+		return err
+	}
+	log.Logger.Info(
+		"Saved match override",
+		zap.String("id", override.ID.Hex()),
+		zap.String("url", override.URL),
+		zap.String("pattern", override.Pattern),
+		zap.String("id_type", override.IDType),
+		zap.Int("platform_id", override.PlatformID),
+	)
+	return nil
+}
+
+// applyMatchOverrides unlinks the game downloads of overrides from their game
+// infos and organizes them again.
+func applyMatchOverrides(ctx context.Context, overrides []*model.MatchOverride) {
+	games, err := crawler.GetGameDownloadsByOverrides(ctx, overrides)
+	if err != nil {
+		log.Logger.Error("Failed to get games", zap.Error(err))
+		return
+	}
+	if len(games) == 0 {
+		return
+	}
+	ids := make([]primitive.ObjectID, 0, len(games))
+	for _, game := range games {
+		ids = append(ids, game.ID)
+	}
+	if err := db.UnlinkGameDownloads(ctx, ids, primitive.NilObjectID); err != nil {
+		log.Logger.Error("Failed to unlink games", zap.Error(err))
+		return
+	}
+	ctx, run := crawler.StartRun(ctx, model.CrawlRunTypeOrganize, "")
+	run.Found(len(games))
+	task.Organize(ctx, games)
+	run.Finish(ctx, ctx.Err())
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"GameDB/internal/config"
+	"GameDB/internal/crawler"
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:  "review",
+	Long: "Review unmatched games and matches with a low confidence",
+}
+
+var reviewListCmd = &cobra.Command{
+	Use:  "list",
+	Long: "List the review queue with the candidates of every game",
+	Run:  reviewListRun,
+}
+
+var reviewAcceptCmd = &cobra.Command{
+	Use:  "accept <game-id> [candidate-id]",
+	Long: "Link a game with a candidate of its last match, the best one by default",
+	Args: cobra.RangeArgs(1, 2),
+	Run:  reviewAcceptRun,
+}
+
+var reviewSetCmd = &cobra.Command{
+	Use:  "set <game-id>",
+	Long: "Link a game with a manually entered ID",
+	Args: cobra.ExactArgs(1),
+	Run:  reviewSetRun,
+}
+
+var reviewNotGameCmd = &cobra.Command{
+	Use:  "not-game <game-id>",
+	Long: "Mark a game as not a game so it is no longer matched",
+	Args: cobra.ExactArgs(1),
+	Run:  reviewNotGameRun,
+}
+
+type reviewCommandConfig struct {
+	Num        int
+	Confidence float64
+	IDType     string
+	ID         int
+}
+
+var reviewCmdCfg reviewCommandConfig
+
+func init() {
+	reviewListCmd.Flags().IntVarP(&reviewCmdCfg.Num, "num", "n", 20, "number of games to list")
+	reviewListCmd.Flags().Float64VarP(&reviewCmdCfg.Confidence, "confidence", "c", 0, "list matches under this confidence (default from config)")
+	reviewSetCmd.Flags().StringVarP(&reviewCmdCfg.IDType, "type", "t", "", "id type (igdb/steam/gog)")
+	reviewSetCmd.Flags().IntVarP(&reviewCmdCfg.ID, "platform-id", "p", 0, "platform id")
+	_ = reviewSetCmd.MarkFlagRequired("type")
+	_ = reviewSetCmd.MarkFlagRequired("platform-id")
+	reviewCmd.AddCommand(reviewListCmd)
+	reviewCmd.AddCommand(reviewAcceptCmd)
+	reviewCmd.AddCommand(reviewSetCmd)
+	reviewCmd.AddCommand(reviewNotGameCmd)
+	RootCmd.AddCommand(reviewCmd)
+}
+
+func reviewListRun(cmd *cobra.Command, args []string) {
+	confidence := reviewCmdCfg.Confidence
+	if confidence <= 0 {
+		confidence = config.Config.ReviewConfidence
+	}
+	games, err := db.GetReviewQueue(cmd.Context(), confidence, reviewCmdCfg.Num)
+	if err != nil {
+		log.Logger.Error("Failed to get review queue", zap.Error(err))
+		return
+	}
+	for _, game := range games {
+		fields := []zap.Field{
+			zap.String("game_id", game.ID.Hex()),
+			zap.String("raw_name", game.RawName),
+			zap.String("name", game.Name),
+		}
+		if game.Match != nil {
+			fields = append(fields,
+				zap.String("source", game.Match.Source),
+				zap.Int("id", game.Match.ID),
+				zap.Float64("confidence", game.Match.Confidence),
+			)
+		}
+		log.Logger.Info("Game", fields...)
+		if game.Match != nil {
+			for _, c := range game.Match.Candidates {
+				log.Logger.Info("Candidate", zap.Int("id", c.ID), zap.String("name", c.Name), zap.Int("year", c.Year), zap.Float64("score", c.Score))
+			}
+		}
+	}
+}
+
+func reviewAcceptRun(cmd *cobra.Command, args []string) {
+	gameID, err := primitive.ObjectIDFromHex(args[0])
+	if err != nil {
+		log.Logger.Error("Failed to parse game id", zap.Error(err))
+		return
+	}
+	candidateID := 0
+	if len(args) > 1 {
+		if candidateID, err = strconv.Atoi(args[1]); err != nil {
+			log.Logger.Error("Failed to parse candidate id", zap.Error(err))
+			return
+		}
+	}
+	if err := crawler.ReviewAcceptCandidate(cmd.Context(), gameID, candidateID); err != nil {
+		log.Logger.Error("Failed to accept candidate", zap.Error(err))
+		return
+	}
+	log.Logger.Info("Accepted candidate", zap.String("game_id", args[0]))
+}
+
+func reviewSetRun(cmd *cobra.Command, args []string) {
+	gameID, err := primitive.ObjectIDFromHex(args[0])
+	if err != nil {
+		log.Logger.Error("Failed to parse game id", zap.Error(err))
+		return
+	}
+	if err := crawler.ReviewAccept(cmd.Context(), gameID, reviewCmdCfg.IDType, reviewCmdCfg.ID); err != nil {
+		log.Logger.Error("Failed to link game", zap.Error(err))
+		return
+	}
+	log.Logger.Info("Linked game", zap.String("game_id", args[0]), zap.String("id_type", reviewCmdCfg.IDType), zap.Int("id", reviewCmdCfg.ID))
+}
+
+func reviewNotGameRun(cmd *cobra.Command, args []string) {
+	gameID, err := primitive.ObjectIDFromHex(args[0])
+	if err != nil {
+		log.Logger.Error("Failed to parse game id", zap.Error(err))
+		return
+	}
+	if err := crawler.ReviewNotAGame(cmd.Context(), gameID); err != nil {
+		log.Logger.Error("Failed to mark game", zap.Error(err))
+		return
+	}
+	log.Logger.Info("Marked as not a game", zap.String("game_id", args[0]))
+}
@@ -1,7 +1,22 @@
 package cmd
 
 import (
+	"GameDB/internal/crawler"
+	"strings"
+
 	"github.com/spf13/cobra"
 )
 
 var RootCmd = &cobra.Command{}
+
+// RefreshSourceUsages lists the registered sources in the help of the
+// --source flags. Scrapers are registered after init, so call it once they
+// are loaded.
+func RefreshSourceUsages() {
+	crawlCmd.Flags().Lookup("source").Usage = sourceUsage("source to crawl")
+	formatCmd.Flags().Lookup("source").Usage = sourceUsage("source to fix")
+}
+
+func sourceUsage(usage string) string {
+	return usage + " (" + strings.Join(crawler.SourceNames(), "/") + ")"
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var runsCmd = &cobra.Command{
+	Use:  "runs",
+	Long: "List recorded crawl and organize runs",
+	Run:  runsRun,
+}
+
+type runsCommandConfig struct {
+	Type     string
+	Source   string
+	Status   string
+	Num      int
+	Failures bool
+}
+
+var runsCmdCfg runsCommandConfig
+
+func init() {
+	runsCmd.Flags().StringVarP(&runsCmdCfg.Type, "type", "t", "", "run type (crawl/organize)")
+	runsCmd.Flags().StringVarP(&runsCmdCfg.Source, "source", "s", "", "source of the runs")
+	runsCmd.Flags().StringVar(&runsCmdCfg.Status, "status", "", "run status (running/success/partial/failed/canceled)")
+	runsCmd.Flags().IntVarP(&runsCmdCfg.Num, "num", "n", 10, "number of runs to list")
+	runsCmd.Flags().BoolVarP(&runsCmdCfg.Failures, "failures", "f", false, "list failed items of every run")
+	RootCmd.AddCommand(runsCmd)
+}
+
+func runsRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	runs, err := db.GetCrawlRuns(ctx, runsCmdCfg.Type, strings.ToLower(runsCmdCfg.Source), runsCmdCfg.Status, runsCmdCfg.Num)
+	if err != nil {
+		log.Logger.Error("Failed to get runs", zap.Error(err))
+		return
+	}
+	for _, run := range runs {
+		log.Logger.Info(
+			"Run",
+			zap.String("id", run.ID.Hex()),
+			zap.String("type", run.Type),
+			zap.String("source", run.Source),
+			zap.String("status", run.Status),
+			zap.Time("started_at", run.StartedAt),
+			zap.Time("finished_at", run.FinishedAt),
+			zap.Ints("pages", run.Pages),
+			zap.Ints("failed_pages", run.FailedPages),
+			zap.Int("found", run.Found),
+			zap.Int("skipped", run.Skipped),
+			zap.Int("saved", run.Saved),
+			zap.Int("matched", run.Matched),
+			zap.Int("failed", run.Failed),
+			zap.String("error", run.Error),
+		)
+		if runsCmdCfg.Failures {
+			for _, failure := range run.Failures {
+				log.Logger.Info("Failure", zap.String("url", failure.Url), zap.String("reason", failure.Reason))
+			}
+		}
+	}
+}
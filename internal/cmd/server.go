@@ -26,5 +26,5 @@ func init() {
 }
 
 func ServerRun(cmd *cobra.Command, args []string) {
-	server.Run(serverCmdCfg.Addr, serverCmdCfg.AutoCrawl)
+	server.Run(cmd.Context(), serverCmdCfg.Addr, serverCmdCfg.AutoCrawl)
 }
@@ -32,24 +32,25 @@ func init() {
 }
 
 func updateRun(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
 	id, err := primitive.ObjectIDFromHex(updateCmdcfx.GameInfoID)
 	if err != nil {
 		log.Logger.Error("Failed to parse game info id", zap.Error(err))
 		return
 	}
-	oldInfo, err := db.GetGameInfoByID(id)
+	oldInfo, err := db.GetGameInfoByID(ctx, id)
 	if err != nil {
 		log.Logger.Error("Failed to get game info", zap.Error(err))
 		return
 	}
-	newInfo, err := crawler.GenerateGameInfo(updateCmdcfx.IDType, updateCmdcfx.ID)
+	newInfo, err := crawler.GenerateGameInfo(ctx, updateCmdcfx.IDType, updateCmdcfx.ID)
 	if err != nil {
 		log.Logger.Error("Failed to generate game info", zap.Error(err))
 		return
 	}
 	newInfo.ID = id
 	newInfo.GameIDs = oldInfo.GameIDs
-	err = db.SaveGameInfo(newInfo)
+	err = db.SaveGameInfo(ctx, newInfo)
 	if err != nil {
 		log.Logger.Error("Failed to save game info", zap.Error(err))
 	}
@@ -9,12 +9,17 @@ import (
 )
 
 type SConfig struct {
-	LogLevel              string       `json:"log_level"`
-	Database              Database     `json:"database"`
-	Redis                 Redis        `json:"redis"`
-	FlareSolverr          FlareSolverr `json:"flaresolverr"`
-	OnlineFix             OnlineFix    `json:"online_fix"`
-	Twitch                Twitch       `json:"twitch"`
+	LogLevel              string            `json:"log_level"`
+	Database              Database          `json:"database"`
+	Redis                 Redis             `json:"redis"`
+	FlareSolverr          FlareSolverr      `json:"flaresolverr"`
+	OnlineFix             OnlineFix         `json:"online_fix"`
+	Twitch                Twitch            `json:"twitch"`
+	Sources               map[string]Source `json:"sources"`
+	ScrapersDir           string            `json:"scrapers_dir"`
+	RulesDir              string            `json:"rules_dir"`
+	ReviewConfidence      float64           `json:"review_confidence"`
+	AdminToken            string            `json:"admin_token"`
 	FlareSolverrAvaliable bool
 	OnlineFixAvaliable    bool
 	MegaAvaliable         bool
@@ -46,6 +51,13 @@ type FlareSolverr struct {
 	Url string `json:"url"`
 }
 
+type Source struct {
+	Concurrency int     `json:"concurrency"`
+	RateLimit   float64 `json:"rate_limit"`
+	Burst       int     `json:"burst"`
+	Feed        string  `json:"feed"`
+}
+
 type OnlineFix struct {
 	User     string `json:"user"`
 	Password string `json:"password"`
@@ -55,10 +67,13 @@ var Config SConfig
 
 func InitConfig() {
 	Config = SConfig{
-		LogLevel:      "info",
-		Database:      Database{},
-		FlareSolverr:  FlareSolverr{},
-		MegaAvaliable: TestMega(),
+		LogLevel:         "info",
+		ScrapersDir:      "scrapers",
+		RulesDir:         "rules",
+		ReviewConfidence: 0.85,
+		Database:         Database{},
+		FlareSolverr:     FlareSolverr{},
+		MegaAvaliable:    TestMega(),
 	}
 	if _, err := os.Stat("config.json"); err == nil {
 		configData, err := os.ReadFile("config.json")
@@ -77,6 +92,12 @@ func loadEnvVariables() {
 	if env := os.Getenv("LOG_LEVEL"); env != "" {
 		Config.LogLevel = env
 	}
+	if env := os.Getenv("SCRAPERS_DIR"); env != "" {
+		Config.ScrapersDir = env
+	}
+	if env := os.Getenv("RULES_DIR"); env != "" {
+		Config.RulesDir = env
+	}
 	if env := os.Getenv("DB_HOST"); env != "" {
 		Config.Database.Host = env
 	}
@@ -119,6 +140,12 @@ func loadEnvVariables() {
 	if env := os.Getenv("ONLINE_FIX_PASSWORD"); env != "" {
 		Config.OnlineFix.Password = env
 	}
+	if env := os.Getenv("REVIEW_CONFIDENCE"); env != "" {
+		Config.ReviewConfidence, _ = strconv.ParseFloat(env, 64)
+	}
+	if env := os.Getenv("ADMIN_TOKEN"); env != "" {
+		Config.AdminToken = env
+	}
 	if env := os.Getenv("AUTO_CRAWL"); env != "" {
 		Config.AutoCrawl, _ = strconv.ParseBool(env)
 	}
@@ -3,6 +3,7 @@ package constant
 const (
 	C1337xBaseURL            = "https://www.1337x.to"
 	FreeGOGListURL           = "https://freegogpcgames.com/a-z-games-list"
+	FreeGOGFeedURL           = "https://freegogpcgames.com/feed/"
 	GOGSearchURL             = "https://embed.gog.com/games/ajax/filtered"
 	GOGDetailsURL            = "https://api.gog.com/products"
 	SteamSearchURL           = "https://store.steampowered.com/search"
@@ -12,9 +13,11 @@ const (
 	HowLongToBeatSearchURL   = "https://howlongtobeat.com/api/search"
 	HowLongToBeatDetailsURL  = "https://howlongtobeat.com/game"
 	XatabBaseURL             = "https://byxatab.com"
+	XatabFeedURL             = "https://byxatab.com/rss.xml"
 	GoogleSearchURL          = "https://www.google.com/search"
 	BingSearchURL            = "https://www.bing.com/search"
 	OnlineFixURL             = "https://online-fix.me"
+	OnlineFixFeedURL         = "https://online-fix.me/rss.xml"
 	OnlineFixCSRFURL         = "https://online-fix.me/engine/ajax/authtoken.php"
 	IGDBGameURL              = "https://api.igdb.com/v4/games"
 	IGDBLanguagesURL         = "https://api.igdb.com/v4/languages"
@@ -24,6 +27,12 @@ const (
 	IGDBInvolvedCompaniesURL = "https://api.igdb.com/v4/involved_companies"
 	IGDBCompaniesURL         = "https://api.igdb.com/v4/companies"
 	IGDBCoversURL            = "https://api.igdb.com/v4/covers"
+	IGDBGenresURL            = "https://api.igdb.com/v4/genres"
+	IGDBThemesURL            = "https://api.igdb.com/v4/themes"
+	IGDBGameModesURL         = "https://api.igdb.com/v4/game_modes"
+	IGDBPerspectivesURL      = "https://api.igdb.com/v4/player_perspectives"
+	IGDBFranchisesURL        = "https://api.igdb.com/v4/franchises"
+	IGDBExternalGamesURL     = "https://api.igdb.com/v4/external_games"
 	TwitchAuthURL            = "https://id.twitch.tv/oauth2/token"
 	Steam250Top250URL        = "https://steam250.com/top250"
 	Steam250BestOfTheYearURL = "https://steam250.com/%v"
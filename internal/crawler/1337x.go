@@ -2,6 +2,7 @@ package crawler
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -18,22 +19,52 @@ import (
 	"go.uber.org/zap"
 )
 
-type Formatter func(string) string
+var (
+	c1337xMagnetRegex = regexp.MustCompile(`magnet:\?[^"]*`)
+	c1337xFileRegex   = regexp.MustCompile(`^(.+?)\s*\(([\d.,]+\s*(?:[KMGT]i?B|Bytes?))\)$`)
+)
 
-func Crawl1337x(source string, page int, formatter Formatter) ([]*model.GameDownload, error) {
-	var resp *utils.FetchResponse
-	var doc *goquery.Document
-	var err error
+type c1337xSource struct {
+	name      string
+	user      string
+	formatter Formatter
+}
+
+func (s *c1337xSource) Name() string {
+	return s.name
+}
+
+func (s *c1337xSource) Author() string {
+	return strings.Replace(s.user, "-torrents", "", -1)
+}
+
+func (s *c1337xSource) BaseURL() string {
+	return constant.C1337xBaseURL
+}
+
+func (s *c1337xSource) Formatter() Formatter {
+	return s.formatter
+}
+
+func (s *c1337xSource) CrawlPage(ctx context.Context, page int) ([]*model.GameDownload, error) {
+	return Crawl1337x(ctx, s.user, page, SourceFormatter(s), sourceConfig(s.name).Concurrency)
+}
+
+func (s *c1337xSource) TotalPageNum(ctx context.Context) (int, error) {
+	return Get1337xTotalPageNum(ctx, s.user)
+}
+
+func Crawl1337x(ctx context.Context, source string, page int, formatter Formatter, concurrency int) ([]*model.GameDownload, error) {
 	requestUrl := fmt.Sprintf("%s/%s/%d/", constant.C1337xBaseURL, source, page)
 	log.Logger.Info("Crawling item", zap.String("url", requestUrl))
-	resp, err = utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: requestUrl,
 	})
 	if err != nil {
 		log.Logger.Error("Failed to fetch", zap.Error(err))
 		return nil, err
 	}
-	doc, err = goquery.NewDocumentFromReader(bytes.NewReader(resp.Data))
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(resp.Data))
 	if err != nil {
 		log.Logger.Error("Failed to parse HTML", zap.Error(err))
 		return nil, err
@@ -44,103 +75,85 @@ func Crawl1337x(source string, page int, formatter Formatter) ([]*model.GameDown
 		nameSelection := trNode.Find(".name").First()
 		if aNode := nameSelection.Find("a").Eq(1); aNode.Length() > 0 {
 			url, _ := aNode.Attr("href")
-			urls = append(urls, url)
+			urls = append(urls, fmt.Sprintf("%s%s", constant.C1337xBaseURL, url))
 		}
 	})
-	var res []*model.GameDownload
-	for i := 0; i < len(urls); i++ {
-		urls[i] = fmt.Sprintf("%s%s", constant.C1337xBaseURL, urls[i])
-		if db.IsGameCrawledByURL(urls[i]) {
+	run := RunFromContext(ctx)
+	run.Found(len(urls))
+	return crawlItems(ctx, concurrency, len(urls), func(i int) (*model.GameDownload, error) {
+		if db.IsGameCrawledByURL(ctx, urls[i]) {
 			log.Logger.Info("Skipping already crawled item", zap.String("url", urls[i]))
-			continue
+			run.Skipped()
+			return nil, nil
 		}
-		requestUrl = urls[i]
-		log.Logger.Info("Crawling item", zap.String("url", requestUrl))
-		resp, err = utils.Fetch(utils.FetchConfig{
-			Url: requestUrl,
-		})
-		if err != nil {
-			log.Logger.Warn("Failed to fetch", zap.String("url", requestUrl), zap.Error(err))
-			continue
-		}
-		var game = &model.GameDownload{}
-		game.Url = urls[i]
-		doc, err = goquery.NewDocumentFromReader(bytes.NewReader(resp.Data))
-		if err != nil {
-			return nil, err
+		game := &model.GameDownload{Url: urls[i]}
+		if err := fetch1337xItem(ctx, game, source, formatter); err != nil {
+			log.Logger.Warn("Failed to crawl item", zap.String("url", urls[i]), zap.Error(err))
+			run.Failed(urls[i], err.Error())
+			return nil, nil
 		}
-		selection := doc.Find(".torrent-detail-page ul.list>li")
-		info := make(map[string]string)
-		selection.Each(func(i int, item *goquery.Selection) {
-			info[strings.TrimSpace(item.Find("strong").Text())] = strings.TrimSpace(item.Find("span").Text())
-		})
-		magnetRegex := regexp.MustCompile(`magnet:\?[^"]*`)
-		magnetRegexRes := magnetRegex.FindStringSubmatch(string(resp.Data))
-		game.Size = info["Total size"]
-		game.RawName = doc.Find("title").Text()
-		game.RawName = strings.Replace(game.RawName, "Download ", "", 1)
-		game.RawName = strings.TrimSpace(strings.Replace(game.RawName, "Torrent | 1337x", " ", 1))
-		game.Name = formatter(game.RawName)
-		game.Magnet = magnetRegexRes[0]
-		game.Author = strings.Replace(source, "-torrents", "", -1)
-		err = db.SaveGameDownload(game)
-		if err != nil {
-			log.Logger.Warn("Failed to save", zap.Error(err))
-			continue
-		}
-		res = append(res, game)
-	}
-	if err != nil {
-		log.Logger.Error("Failed to add game item", zap.Error(err))
-	}
-	return res, nil
+		return saveItem(ctx, urls[i], game)
+	})
 }
 
-func Crawl1337xMulti(source string, pages []int, formatter Formatter) (res []*model.GameDownload, err error) {
-	var items []*model.GameDownload
-	totalPageNum, err := Get1337xTotalPageNum(source)
+// fetch1337xItem fills a game download from its torrent detail page.
+func fetch1337xItem(ctx context.Context, game *model.GameDownload, source string, formatter Formatter) error {
+	log.Logger.Info("Crawling item", zap.String("url", game.Url))
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url: game.Url,
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	for _, page := range pages {
-		if page > totalPageNum {
-			log.Logger.Warn("Current page exceed total page", zap.Int("page", page))
-			continue
-		}
-		items, err = Crawl1337x(source, page, formatter)
-		res = append(res, items...)
-		if err != nil {
-			return nil, err
-		}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(resp.Data))
+	if err != nil {
+		return err
 	}
-	log.Logger.Info("Crawled finished", zap.Int("num", len(res)))
-	return res, nil
+	selection := doc.Find(".torrent-detail-page ul.list>li")
+	info := make(map[string]string)
+	selection.Each(func(i int, item *goquery.Selection) {
+		info[strings.TrimSpace(item.Find("strong").Text())] = strings.TrimSpace(item.Find("span").Text())
+	})
+	magnetRegexRes := c1337xMagnetRegex.FindStringSubmatch(string(resp.Data))
+	if len(magnetRegexRes) == 0 {
+		return errors.New("magnet not found")
+	}
+	game.Size = info["Total size"]
+	game.RawName = doc.Find("title").Text()
+	game.RawName = strings.Replace(game.RawName, "Download ", "", 1)
+	game.RawName = strings.TrimSpace(strings.Replace(game.RawName, "Torrent | 1337x", " ", 1))
+	game.Name = formatter(game.RawName)
+	game.Magnet = magnetRegexRes[0]
+	game.Files = get1337xFiles(doc)
+	game.Author = strings.Replace(source, "-torrents", "", -1)
+	return nil
 }
 
-func Crawl1337xAll(source string, formatter Formatter) (res []*model.GameDownload, err error) {
-	totalPageNum, err := Get1337xTotalPageNum(source)
-	if err != nil {
-		return nil, err
-	}
-	var items []*model.GameDownload
-	for i := 1; i <= totalPageNum; i++ {
-		items, err = Crawl1337x(source, i, formatter)
-		res = append(res, items...)
+// get1337xFiles reads the file list of a torrent detail page, folders are not
+// listed there so paths are file names only.
+func get1337xFiles(doc *goquery.Document) []model.TorrentFile {
+	var files []model.TorrentFile
+	doc.Find(".file-content li").Each(func(i int, li *goquery.Selection) {
+		res := c1337xFileRegex.FindStringSubmatch(strings.TrimSpace(li.Text()))
+		if res == nil {
+			return
+		}
+		length, err := utils.ParseSize(res[2])
 		if err != nil {
-			return nil, err
+			return
 		}
-	}
-	log.Logger.Info("Crawled finished", zap.Int("num", len(res)))
-	return res, nil
+		files = append(files, model.TorrentFile{Path: res[1], Length: length})
+	})
+	return files
 }
 
-func Get1337xTotalPageNum(source string) (int, error) {
+func Get1337xTotalPageNum(ctx context.Context, source string) (int, error) {
 	var resp *utils.FetchResponse
 	var doc *goquery.Document
 	var err error
 
 	requestUrl := fmt.Sprintf("%s/%s/%d/", constant.C1337xBaseURL, source, 1)
-	resp, err = utils.Fetch(utils.FetchConfig{
+	resp, err = utils.Fetch(ctx, utils.FetchConfig{
 		Url: requestUrl,
 	})
 	if err != nil {
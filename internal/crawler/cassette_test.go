@@ -0,0 +1,228 @@
+package crawler
+
+import (
+	"GameDB/internal/config"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"GameDB/internal/utils"
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// The cassettes in testdata/cassettes are synthetic: they are written by hand
+// after the markup and APIs of the sites, with made-up torrents and hashes,
+// and are not recordings. The tests check what the crawlers make of them
+// rather than the invented values, so a cassette can be replaced with a real
+// recording (utils.CassetteRecord) without rewriting its test.
+
+// useCassette routes every fetch of the test through the named cassette.
+func useCassette(t *testing.T, name string) {
+	t.Helper()
+	log.Logger = zap.NewNop()
+	cassette, err := utils.NewCassette(filepath.Join("testdata", "cassettes", name+".json"), utils.CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utils.SetTransport(cassette)
+	t.Cleanup(func() { utils.SetTransport(nil) })
+}
+
+var magnetRegex = regexp.MustCompile(`^magnet:\?xt=urn:btih:[0-9a-fA-F]{40}(&|$)`)
+
+// checkGameDownload checks the fields every crawled game download has.
+func checkGameDownload(t *testing.T, item *model.GameDownload, author string) {
+	t.Helper()
+	if item.RawName == "" || item.Name == "" || !strings.Contains(item.RawName, item.Name) {
+		t.Errorf("name %q is not cleaned from raw name %q", item.Name, item.RawName)
+	}
+	if item.Author != author {
+		t.Errorf("author = %q, want %q", item.Author, author)
+	}
+	if !magnetRegex.MatchString(item.Magnet) {
+		t.Errorf("magnet = %q", item.Magnet)
+	}
+	if _, err := utils.ParseSize(item.Size); err != nil {
+		t.Errorf("size %q: %v", item.Size, err)
+	}
+}
+
+// checkTorrent checks that the magnet of a game download comes from its
+// .torrent file.
+func checkTorrent(t *testing.T, item *model.GameDownload) {
+	t.Helper()
+	if len(item.Torrent) == 0 {
+		t.Fatal("torrent not kept")
+	}
+	magnet, _, err := utils.ConvertTorrentToMagnet(item.Torrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := utils.ParseMagnet(magnet)
+	got, err := utils.ParseMagnet(item.Magnet)
+	if err != nil || got.InfoHash != want.InfoHash {
+		t.Errorf("magnet %q is not the one of the torrent %q", item.Magnet, magnet)
+	}
+}
+
+func TestCassette1337x(t *testing.T) {
+	useCassette(t, "1337x")
+	ctx := context.Background()
+	total, err := Get1337xTotalPageNum(ctx, DODIName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total < 2 {
+		t.Errorf("total pages = %d, want the last page of the pagination", total)
+	}
+	item := &model.GameDownload{Url: "https://www.1337x.to/torrent/5923811/Hollow-Knight-v1-5-78-11833-DODI-Repack/"}
+	if err := fetch1337xItem(ctx, item, DODIName, DODIFormatter); err != nil {
+		t.Fatal(err)
+	}
+	checkGameDownload(t, item, "DODI")
+	if len(item.Files) == 0 {
+		t.Error("files not listed")
+	}
+	for _, file := range item.Files {
+		if file.Path == "" {
+			t.Errorf("file without path in %+v", item.Files)
+		}
+	}
+}
+
+func TestCassetteXatab(t *testing.T) {
+	useCassette(t, "xatab")
+	ctx := context.Background()
+	total, err := GetXatabTotalPageNum(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total < 2 {
+		t.Errorf("total pages = %d, want the last page of the pagination", total)
+	}
+	item := &model.GameDownload{Url: "https://byxatab.com/games/torrent_igry/platformery/16305-hollow-knight.html"}
+	if err := fetchXatabItem(ctx, item); err != nil {
+		t.Fatal(err)
+	}
+	checkGameDownload(t, item, "Xatab")
+	checkTorrent(t, item)
+}
+
+func TestCassetteFreeGOG(t *testing.T) {
+	useCassette(t, "freegog")
+	item := &model.GameDownload{Url: "https://freegogpcgames.com/5613/hollow-knight/"}
+	if err := fetchFreeGOGItem(context.Background(), item); err != nil {
+		t.Fatal(err)
+	}
+	checkGameDownload(t, item, "FreeGOG")
+	if strings.HasSuffix(item.Name, "-") || strings.Contains(item.Name, "v1.") {
+		t.Errorf("version left in name %q", item.Name)
+	}
+}
+
+func TestCassetteOnlineFix(t *testing.T) {
+	user, password := config.Config.OnlineFix.User, config.Config.OnlineFix.Password
+	config.Config.OnlineFix.User, config.Config.OnlineFix.Password = "user", "password"
+	t.Cleanup(func() {
+		config.Config.OnlineFix.User, config.Config.OnlineFix.Password = user, password
+	})
+	useCassette(t, "onlinefix")
+	ctx := context.Background()
+	if err := LoginOnlineFix(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cookiesMu.Lock()
+		cookies = nil
+		cookiesMu.Unlock()
+	})
+	if c := onlineFixCookies(); c["PHPSESSID"] == "" || c["dle_user_id"] == "" {
+		t.Errorf("cookies = %v", c)
+	}
+	total, err := GetOnlineFixTotalPageNum(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total < 2 {
+		t.Errorf("total pages = %d, want the last page of the pagination", total)
+	}
+	item := &model.GameDownload{Url: "https://online-fix.me/games/adventure/8824-hollow-knight-po-seti.html"}
+	if err := fetchOnlineFixItem(ctx, item); err != nil {
+		t.Fatal(err)
+	}
+	checkGameDownload(t, item, "OnlineFix")
+	checkTorrent(t, item)
+}
+
+func TestCassetteIGDB(t *testing.T) {
+	ctx := context.Background()
+	token := TwitchToken
+	TwitchToken = "test"
+	t.Cleanup(func() { TwitchToken = token })
+	useCassette(t, "igdb")
+	detail, err := GetIGDBAppDetail(ctx, 14593)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.ID != 14593 || detail.Name == "" || detail.FirstReleaseDate == 0 {
+		t.Errorf("detail = %+v", detail)
+	}
+	externals, err := GetIGDBExternalGames(ctx, 14593)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := &model.GameInfo{}
+	if !setExternalIDs(item, externals) {
+		t.Fatal("no external ID set")
+	}
+	if item.SteamID == 0 || item.GOGID == 0 || item.StoreIDs["epic"] == "" {
+		t.Errorf("steam %d gog %d stores %v", item.SteamID, item.GOGID, item.StoreIDs)
+	}
+}
+
+func TestCassetteSteam(t *testing.T) {
+	useCassette(t, "steam")
+	ctx := context.Background()
+	detail, err := GetSteamAppDetail(ctx, 367520)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Data.SteamAppid != 367520 || detail.Data.Name == "" {
+		t.Errorf("detail = %+v", detail.Data)
+	}
+	res, err := GetSteamIDFromSearchPage(ctx, "Hollow Knight", 2017)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != detail.Data.SteamAppid {
+		t.Errorf("steam id = %d, want the app %d", res.ID, detail.Data.SteamAppid)
+	}
+}
+
+func TestCassetteGOG(t *testing.T) {
+	useCassette(t, "gog")
+	ctx := context.Background()
+	res, err := MatchGOG(ctx, "Hollow Knight", 2017)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Source != "gog" || res.ID == 0 || res.Confidence < MinMatchConfidence {
+		t.Errorf("match = %+v", res)
+	}
+	info, err := GenerateGOGGameInfo(ctx, res.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.GOGID != res.ID || info.Name != res.Name || info.Description == "" {
+		t.Errorf("game info = %+v", info)
+	}
+	for _, screenshot := range info.Screenshots {
+		if strings.Contains(screenshot, "{formatter}") {
+			t.Errorf("screenshot %q is a template", screenshot)
+		}
+	}
+}
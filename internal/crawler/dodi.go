@@ -1,7 +1,6 @@
 package crawler
 
 import (
-	"GameDB/internal/model"
 	"GameDB/internal/utils"
 	"regexp"
 	"strings"
@@ -9,12 +8,8 @@ import (
 
 const DODIName string = "DODI-torrents"
 
-func CrawlDODIMulti(pages []int) ([]*model.GameDownload, error) {
-	return Crawl1337xMulti(DODIName, pages, DODIFormatter)
-}
-
-func CrawlDODIAll() ([]*model.GameDownload, error) {
-	return Crawl1337xAll(DODIName, DODIFormatter)
+func init() {
+	RegisterSource(&c1337xSource{name: "dodi", user: DODIName, formatter: DODIFormatter})
 }
 
 var dodiRegexps = []*regexp.Regexp{
@@ -24,7 +19,7 @@ var dodiRegexps = []*regexp.Regexp{
 
 func DODIFormatter(name string) string {
 	name = strings.Replace(name, "- [DODI Repack]", "", -1)
-	name = strings.Replace(name, "- Campaign Remastered", "", -1)
+	name = strings.Replace(name, "- Campaign Remastered", "Remastered", -1)
 	name = strings.Replace(name, "- Remastered", "", -1)
 	if index := strings.Index(name, "+"); index != -1 {
 		name = name[:index]
@@ -0,0 +1,154 @@
+package crawler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"GameDB/internal/utils"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/html/charset"
+)
+
+// FeedSource is a source that lists its new items in an RSS or Atom feed, so
+// they can be crawled without fetching the list pages.
+type FeedSource interface {
+	Source
+	// FeedURL returns an empty string if the source has no feed
+	FeedURL() string
+	// CrawlItem crawls and saves the detail page at url, a nil item means
+	// the item was skipped
+	CrawlItem(ctx context.Context, url string, updateFlag string) (*model.GameDownload, error)
+}
+
+type feedEntry struct {
+	Link string
+	Date time.Time
+}
+
+type feedDocument struct {
+	Items []struct {
+		Link    string `xml:"link"`
+		PubDate string `xml:"pubDate"`
+	} `xml:"channel>item"`
+	Entries []struct {
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Updated   string `xml:"updated"`
+		Published string `xml:"published"`
+	} `xml:"entry"`
+}
+
+// feedURL returns the feed configured for the source or defaultURL.
+func feedURL(name string, defaultURL string) string {
+	if cfg := sourceConfig(name); cfg.Feed != "" {
+		return cfg.Feed
+	}
+	return defaultURL
+}
+
+func fetchFeed(ctx context.Context, url string) ([]feedEntry, error) {
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url: url,
+	})
+	if err != nil {
+		log.Logger.Error("Failed to fetch", zap.Error(err))
+		return nil, err
+	}
+	var doc feedDocument
+	decoder := xml.NewDecoder(bytes.NewReader(resp.Data))
+	decoder.CharsetReader = charset.NewReaderLabel
+	if err = decoder.Decode(&doc); err != nil {
+		log.Logger.Error("Failed to parse feed", zap.Error(err))
+		return nil, err
+	}
+	var entries []feedEntry
+	for _, item := range doc.Items {
+		entries = append(entries, feedEntry{
+			Link: strings.TrimSpace(item.Link),
+			Date: parseFeedDate(item.PubDate),
+		})
+	}
+	for _, entry := range doc.Entries {
+		var link string
+		for _, l := range entry.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = l.Href
+				break
+			}
+		}
+		date := entry.Updated
+		if date == "" {
+			date = entry.Published
+		}
+		entries = append(entries, feedEntry{
+			Link: strings.TrimSpace(link),
+			Date: parseFeedDate(date),
+		})
+	}
+	res := entries[:0]
+	for _, entry := range entries {
+		if entry.Link != "" {
+			res = append(res, entry)
+		}
+	}
+	return res, nil
+}
+
+// parseFeedDate parses an RSS or Atom date, it returns the zero time if the
+// date is missing or invalid.
+func parseFeedDate(date string) time.Time {
+	date = strings.TrimSpace(date)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// CrawlFeed crawls the entries of the source's feed that are new or dated
+// after their last crawl, using the source's detail page parser. Feeds do not
+// carry the update flag of the list pages, so entries are matched by URL and
+// the stored flag is kept.
+func CrawlFeed(ctx context.Context, src FeedSource) ([]*model.GameDownload, error) {
+	url := src.FeedURL()
+	if url == "" {
+		return nil, errors.New("source has no feed")
+	}
+	applyRateLimit(src)
+	log.Logger.Info("Crawling feed", zap.String("source", src.Name()), zap.String("url", url))
+	entries, err := fetchFeed(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	run := RunFromContext(ctx)
+	run.Found(len(entries))
+	res, err := crawlItems(ctx, sourceConfig(src.Name()).Concurrency, len(entries), func(i int) (*model.GameDownload, error) {
+		item, err := db.GetGameDownloadByUrl(ctx, entries[i].Link)
+		if err != nil {
+			log.Logger.Error("Failed to get game item", zap.String("url", entries[i].Link), zap.Error(err))
+			run.Failed(entries[i].Link, err.Error())
+			return nil, nil
+		}
+		if !item.ID.IsZero() && !entries[i].Date.After(item.UpdatedAt) {
+			log.Logger.Info("Skipping already crawled item", zap.String("url", entries[i].Link))
+			run.Skipped()
+			return nil, nil
+		}
+		return src.CrawlItem(ctx, entries[i].Link, item.UpdateFlag)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Logger.Info("Crawled finished", zap.String("source", src.Name()), zap.Int("num", len(res)))
+	return res, nil
+}
@@ -1,28 +1,26 @@
 package crawler
 
 import (
-	"GameDB/internal/model"
 	"regexp"
 	"strings"
 )
 
 const FitgirlName string = "FitGirl-torrents"
 
-func CrawlFitgirlMulti(pages []int) ([]*model.GameDownload, error) {
-	return Crawl1337xMulti(FitgirlName, pages, DODIFormatter)
-}
-
-func CrawlFitgirlAll() ([]*model.GameDownload, error) {
-	return Crawl1337xAll(FitgirlName, DODIFormatter)
+func init() {
+	RegisterSource(&c1337xSource{name: "fitgirl", user: FitgirlName, formatter: FitgirlFormatter})
 }
 
 var fitgirlRegexps = []*regexp.Regexp{
 	regexp.MustCompile(`(?i)\(.*\)`),
 	regexp.MustCompile(`(?i)\[.*?\]`),
 	regexp.MustCompile(`(?i)-.*?(Edition|Bundle|Pack|Set|Remake|Collection)`),
+	regexp.MustCompile(`(?i)\s[-–]\s*v\d.*$`),
 }
 
 func FitgirlFormatter(name string) string {
+	// remasters released as their own game keep their edition
+	name = strings.Replace(name, "- Legendary Edition", "Legendary Edition", -1)
 	for _, re := range fitgirlRegexps {
 		name = re.ReplaceAllString(name, "")
 	}
@@ -0,0 +1,60 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type formatterCase struct {
+	Raw  string `yaml:"raw"`
+	Name string `yaml:"name"`
+	// Bad explains why the formatter is known not to give Name yet
+	Bad string `yaml:"bad"`
+}
+
+// TestFormatters runs every formatter against the corpus in
+// testdata/formatters/<source>.yaml. The corpus is written by hand and holds
+// the names the games should be matched with, cases marked bad are known to
+// fail and must be unmarked once they pass.
+func TestFormatters(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "formatters", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no formatter corpus found")
+	}
+	for _, file := range files {
+		source := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		t.Run(source, func(t *testing.T) {
+			src, ok := GetSource(source)
+			if !ok {
+				t.Fatalf("unknown source %q", source)
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cases []*formatterCase
+			if err := yaml.Unmarshal(data, &cases); err != nil {
+				t.Fatal(err)
+			}
+			formatter := src.Formatter()
+			for _, c := range cases {
+				name := formatter(c.Raw)
+				switch {
+				case c.Bad != "" && name == c.Name:
+					t.Errorf("%s(%q) = %q now, drop its bad mark", source, c.Raw, name)
+				case c.Bad != "":
+					t.Logf("%s(%q) = %q, want %q: %s", source, c.Raw, name, c.Name, c.Bad)
+				case name != c.Name:
+					t.Errorf("%s(%q) = %q, want %q", source, c.Raw, name, c.Name)
+				}
+			}
+		})
+	}
+}
@@ -7,9 +7,10 @@ import (
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
 	"bytes"
+	"context"
 	"encoding/base64"
+	"errors"
 	"html"
-	"math"
 	"regexp"
 	"strings"
 
@@ -17,9 +18,46 @@ import (
 	"go.uber.org/zap"
 )
 
-func CrawlFreeGOG(num int) ([]*model.GameDownload, error) {
-	haveCrawled := 0
-	resp, err := utils.Fetch(utils.FetchConfig{
+type freeGOGSource struct{}
+
+func init() {
+	RegisterSource(&freeGOGSource{})
+}
+
+func (s *freeGOGSource) Name() string {
+	return "freegog"
+}
+
+func (s *freeGOGSource) Author() string {
+	return "FreeGOG"
+}
+
+func (s *freeGOGSource) BaseURL() string {
+	return constant.FreeGOGListURL
+}
+
+func (s *freeGOGSource) Formatter() Formatter {
+	return FreeGOGFormatter
+}
+
+func (s *freeGOGSource) FeedURL() string {
+	return feedURL(s.Name(), constant.FreeGOGFeedURL)
+}
+
+func (s *freeGOGSource) CrawlItem(ctx context.Context, url string, updateFlag string) (*model.GameDownload, error) {
+	item, err := crawlFreeGOGItem(ctx, url, updateFlag)
+	if item == nil || err != nil {
+		return nil, err
+	}
+	return saveItem(ctx, url, item)
+}
+
+func (s *freeGOGSource) CrawlList(ctx context.Context, num int) ([]*model.GameDownload, error) {
+	return CrawlFreeGOG(ctx, num, sourceConfig(s.Name()).Concurrency)
+}
+
+func CrawlFreeGOG(ctx context.Context, num int, concurrency int) ([]*model.GameDownload, error) {
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.FreeGOGListURL,
 	})
 	if err != nil {
@@ -39,73 +77,83 @@ func CrawlFreeGOG(num int) ([]*model.GameDownload, error) {
 		updateFlags = append(updateFlags, s.Text()+s.AttrOr("href", ""))
 	})
 
-	res := []*model.GameDownload{}
-	for i := 0; i < len(urls); i++ {
-		if haveCrawled >= num {
-			break
+	run := RunFromContext(ctx)
+	run.Found(len(urls))
+	limit := newCrawlLimit(num)
+	return crawlItems(ctx, concurrency, len(urls), func(i int) (*model.GameDownload, error) {
+		if limit.full() {
+			return nil, nil
 		}
-		if db.IsFreeGOGCrawled(updateFlags[i]) {
+		if db.IsGameCrawled(ctx, updateFlags[i], "freegog") {
 			log.Logger.Info("Skipping already crawled item", zap.String("URL", urls[i]))
-			continue
+			run.Skipped()
+			return nil, nil
 		}
-		log.Logger.Info("Crawling item", zap.String("URL", urls[i]))
-		resp, err := utils.Fetch(utils.FetchConfig{
-			Url: urls[i],
-		})
-		if err != nil {
-			log.Logger.Warn("Failed to fetch", zap.Error(err), zap.String("URL", urls[i]))
-			continue
+		if !limit.reserve() {
+			return nil, nil
 		}
-		item, err := db.GetGameDownloadByUrl(urls[i])
-		if err != nil {
-			log.Logger.Error("Failed to get game item", zap.Error(err))
+		item, err := crawlFreeGOGItem(ctx, urls[i], updateFlags[i])
+		if item == nil || err != nil {
+			limit.release()
 			return nil, err
 		}
-		item.Url = urls[i]
-		item.UpdateFlag = updateFlags[i]
-		rawTitleRegex := regexp.MustCompile(`(?i)<h1 class="entry-title">(.*?)</h1>`)
-		rawTitleRegexRes := rawTitleRegex.FindStringSubmatch(string(resp.Data))
-		if len(rawTitleRegexRes) > 1 {
-			rawName := rawTitleRegexRes[1]
-			rawName = html.UnescapeString(rawName)
-			rawName = strings.Replace(rawName, "–", "-", -1)
-			item.RawName = rawName
-		} else {
-			log.Logger.Warn("Failed to get title", zap.String("url", urls[i]))
-			continue
-		}
-		item.Name = FreeGOGFormatter(item.RawName)
-		sizeRegex := regexp.MustCompile(`(?i)>Size:\s?(.*?)<`)
-		sizeRegexRes := sizeRegex.FindStringSubmatch(string(resp.Data))
-		if len(sizeRegexRes) > 1 {
-			item.Size = sizeRegexRes[1]
-		}
-		magnetRegex := regexp.MustCompile(`<a class="download-btn" href="https://gdl.freegogpcgames.xyz/download-gen\.php\?url=(.*?)"`)
-		magnetRegexRes := magnetRegex.FindStringSubmatch(string(resp.Data))
-		if len(magnetRegexRes) > 1 {
-			magnet, err := base64.StdEncoding.DecodeString(magnetRegexRes[1])
-			if err != nil {
-				log.Logger.Warn("Failed to decode magnet", zap.String("url", urls[i]))
-				continue
-			}
-			item.Magnet = string(magnet)
-		} else {
-			log.Logger.Warn("Failed to get magnet", zap.String("url", urls[i]))
-			continue
-		}
-		item.Author = "FreeGOG"
-		res = append(res, item)
-		haveCrawled++
-		err = db.SaveGameDownload(item)
-		if err != nil {
-			log.Logger.Error("Failed to save game item", zap.Error(err))
-		}
+		return saveItem(ctx, urls[i], item)
+	})
+}
+
+func crawlFreeGOGItem(ctx context.Context, url string, updateFlag string) (*model.GameDownload, error) {
+	run := RunFromContext(ctx)
+	item, err := db.GetGameDownloadByUrl(ctx, url)
+	if err != nil {
+		log.Logger.Error("Failed to get game item", zap.Error(err))
+		return nil, err
 	}
-	return res, nil
+	item.Url = url
+	item.UpdateFlag = updateFlag
+	if err := fetchFreeGOGItem(ctx, item); err != nil {
+		log.Logger.Warn("Failed to crawl item", zap.String("url", url), zap.Error(err))
+		run.Failed(url, err.Error())
+		return nil, nil
+	}
+	return item, nil
 }
 
-func CrawlFreeGOGAll() ([]*model.GameDownload, error) {
-	return CrawlFreeGOG(math.MaxInt)
+// fetchFreeGOGItem fills a game download from its page.
+func fetchFreeGOGItem(ctx context.Context, item *model.GameDownload) error {
+	log.Logger.Info("Crawling item", zap.String("URL", item.Url))
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url: item.Url,
+	})
+	if err != nil {
+		return err
+	}
+	rawTitleRegex := regexp.MustCompile(`(?i)<h1 class="entry-title">(.*?)</h1>`)
+	rawTitleRegexRes := rawTitleRegex.FindStringSubmatch(string(resp.Data))
+	if len(rawTitleRegexRes) < 2 {
+		return errors.New("failed to get title")
+	}
+	rawName := rawTitleRegexRes[1]
+	rawName = html.UnescapeString(rawName)
+	rawName = strings.Replace(rawName, "–", "-", -1)
+	item.RawName = rawName
+	item.Name = SourceFormatter(&freeGOGSource{})(item.RawName)
+	sizeRegex := regexp.MustCompile(`(?i)>Size:\s?(.*?)<`)
+	sizeRegexRes := sizeRegex.FindStringSubmatch(string(resp.Data))
+	if len(sizeRegexRes) > 1 {
+		item.Size = sizeRegexRes[1]
+	}
+	magnetRegex := regexp.MustCompile(`<a class="download-btn" href="https://gdl.freegogpcgames.xyz/download-gen\.php\?url=(.*?)"`)
+	magnetRegexRes := magnetRegex.FindStringSubmatch(string(resp.Data))
+	if len(magnetRegexRes) < 2 {
+		return errors.New("failed to get magnet")
+	}
+	magnet, err := base64.StdEncoding.DecodeString(magnetRegexRes[1])
+	if err != nil {
+		return errors.New("failed to decode magnet")
+	}
+	item.Magnet = string(magnet)
+	item.Author = "FreeGOG"
+	return nil
 }
 
 var freeGOGRegexps = []*regexp.Regexp{
@@ -4,30 +4,48 @@ import (
 	"GameDB/internal/db"
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
+	"context"
 	"errors"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func GenerateGameInfo(idtype string, id int) (*model.GameInfo, error) {
+func GenerateGameInfo(ctx context.Context, idtype string, id int) (*model.GameInfo, error) {
 	switch idtype {
 	case "steam":
-		return GenerateSteamGameInfo(id)
+		return GenerateSteamGameInfo(ctx, id)
 	case "gog":
-		return GenerateGOGGameInfo(id)
+		return GenerateGOGGameInfo(ctx, id)
 	case "igdb":
-		return GenerateIGDBGameInfo(id)
+		return GenerateIGDBGameInfo(ctx, id)
 	default:
 		return nil, errors.New("Invalid ID type")
 	}
 }
 
-func AddGameInfoManually(gameID primitive.ObjectID, idtype string, id int) error {
-	info, err := GenerateGameInfo(idtype, id)
+// AddGameInfoManually links a game download with the game info of idtype and
+// id, the game info is generated if it does not exist yet.
+func AddGameInfoManually(ctx context.Context, gameID primitive.ObjectID, idtype string, id int) error {
+	info, err := getOrGenerateGameInfo(ctx, idtype, id)
 	if err != nil {
 		return err
 	}
+	return linkGameInfo(ctx, info, gameID)
+}
+
+// getOrGenerateGameInfo returns the game info of idtype and id, generated if
+// it does not exist yet. The generated game info is not saved.
+func getOrGenerateGameInfo(ctx context.Context, idtype string, id int) (*model.GameInfo, error) {
+	info, err := db.GetGameInfoByPlatformID(ctx, idtype, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return GenerateGameInfo(ctx, idtype, id)
+	}
+	return info, err
+}
+
+func linkGameInfo(ctx context.Context, info *model.GameInfo, gameID primitive.ObjectID) error {
 	info.GameIDs = append(info.GameIDs, gameID)
 	info.GameIDs = utils.Unique(info.GameIDs)
-	return db.SaveGameInfo(info)
+	return db.SaveGameInfo(ctx, info)
 }
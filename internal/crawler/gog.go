@@ -8,17 +8,19 @@ import (
 	"GameDB/internal/log"
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
-func _GetGOGID(name string, prepare bool) (int, error) {
+func _GetGOGID(ctx context.Context, name string, year int, prepare bool) (*model.MatchResult, error) {
 	log.Logger.Debug("Get GOG ID", zap.String("key", name))
 
 	if prepare {
@@ -30,48 +32,57 @@ func _GetGOGID(name string, prepare bool) (int, error) {
 	params.Add("mediaType", "game")
 	params.Add("search", name)
 	baseURL.RawQuery = params.Encode()
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: baseURL.String(),
 	})
 	if err != nil {
 		log.Logger.Error("Failed to fetch", zap.String("url", baseURL.String()), zap.Error(err))
-		return 0, err
+		return nil, err
 	}
 	data := model.GOGSearch{}
 	err = json.Unmarshal(resp.Data, &data)
 	if err != nil {
 		log.Logger.Error("Failed to unmarshal JSON", zap.Error(err))
-		return 0, err
+		return nil, err
 	}
-	maxSim := 0.0
-	maxSimID := 0
+	candidates := make([]*matchCandidate, 0, len(data.Products))
 	for _, item := range data.Products {
-		sim := utils.Similarity(item.Title, name)
-		if sim > maxSim && sim >= 0.8 {
-			maxSim = sim
-			maxSimID = item.ID
+		c := &matchCandidate{
+			ID:    item.ID,
+			Name:  item.Title,
+			NotPC: !item.WorksOn.Windows,
 		}
+		// release dates are unix timestamps
+		if date, ok := item.ReleaseDate.(float64); ok && date > 0 {
+			c.Year = time.Unix(int64(date), 0).UTC().Year()
+		}
+		candidates = append(candidates, c)
 	}
-	if maxSimID == 0 {
+	res, err := matchCandidates("gog", name, year, candidates)
+	if err != nil {
 		log.Logger.Warn("GOG ID not found", zap.String("key", name))
-		return 0, errors.New("GOG ID not found")
+		return res, err
 	}
-	return maxSimID, nil
+	return res, nil
 }
 
-func GetGOGID(name string) (int, error) {
-	id, err := _GetGOGID(name, false)
-	if err == nil {
-		return id, nil
-	}
-	id, err = _GetGOGID(name, true)
-	if err == nil {
-		return id, nil
+// MatchGOG matches the name of a game with GOG, year is the release year of
+// the game or 0 if unknown.
+func MatchGOG(ctx context.Context, name string, year int) (*model.MatchResult, error) {
+	return matchPrepared(func(prepare bool) (*model.MatchResult, error) {
+		return _GetGOGID(ctx, name, year, prepare)
+	})
+}
+
+func GetGOGID(ctx context.Context, name string) (int, error) {
+	res, err := MatchGOG(ctx, name, 0)
+	if err != nil {
+		return 0, errors.New("GOG ID not found")
 	}
-	return 0, errors.New("GOG ID not found")
+	return res.ID, nil
 }
 
-func GetGOGIDCache(name string) (int, error) {
+func GetGOGIDCache(ctx context.Context, name string) (int, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("gog_id:%s", name)
 		val, exist := cache.Redis.Get(key)
@@ -82,7 +93,7 @@ func GetGOGIDCache(name string) (int, error) {
 			}
 			return id, nil
 		} else {
-			id, err := GetGOGID(key)
+			id, err := GetGOGID(ctx, key)
 			if err != nil {
 				return 0, err
 			}
@@ -93,17 +104,17 @@ func GetGOGIDCache(name string) (int, error) {
 			return id, nil
 		}
 	} else {
-		return GetGOGID(name)
+		return GetGOGID(ctx, name)
 	}
 }
 
-func GetGOGAppDetail(id int) (*model.GOGAppDetail, error) {
+func GetGOGAppDetail(ctx context.Context, id int) (*model.GOGAppDetail, error) {
 	baseURL, _ := url.Parse(fmt.Sprintf("%s/%v", constant.GOGDetailsURL, id))
 	params := url.Values{}
 	params.Add("expand", "downloads,expanded_dlcs,description,screenshots,videos,related_products,changelog")
 	params.Add("locale", "zh")
 	baseURL.RawQuery = params.Encode()
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: baseURL.String(),
 	})
 	if err != nil {
@@ -121,7 +132,7 @@ func GetGOGAppDetail(id int) (*model.GOGAppDetail, error) {
 	return &data, nil
 }
 
-func GetGOGAppDetailCache(id int) (*model.GOGAppDetail, error) {
+func GetGOGAppDetailCache(ctx context.Context, id int) (*model.GOGAppDetail, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("gog_app:%d", id)
 		val, exist := cache.Redis.Get(key)
@@ -134,7 +145,7 @@ func GetGOGAppDetailCache(id int) (*model.GOGAppDetail, error) {
 			}
 			return &data, nil
 		} else {
-			data, err := GetGOGAppDetail(id)
+			data, err := GetGOGAppDetail(ctx, id)
 			if err != nil {
 				return nil, err
 			}
@@ -150,13 +161,13 @@ func GetGOGAppDetailCache(id int) (*model.GOGAppDetail, error) {
 			return data, nil
 		}
 	} else {
-		return GetGOGAppDetail(id)
+		return GetGOGAppDetail(ctx, id)
 	}
 }
 
-func GenerateGOGGameInfo(id int) (*model.GameInfo, error) {
+func GenerateGOGGameInfo(ctx context.Context, id int) (*model.GameInfo, error) {
 	item := &model.GameInfo{}
-	info, err := GetGOGAppDetailCache(id)
+	info, err := GetGOGAppDetailCache(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -175,18 +186,18 @@ func GenerateGOGGameInfo(id int) (*model.GameInfo, error) {
 	return item, nil
 }
 
-func ProcessGameWithGOG(game *model.GameDownload) (*model.GameInfo, error) {
-	id, err := GetGOGID(game.Name)
+func ProcessGameWithGOG(ctx context.Context, game *model.GameDownload, overrides *MatchOverrides) (*model.GameInfo, error) {
+	id, err := matchGame(ctx, game, overrides, "gog", MatchGOG)
 	if err != nil {
 		return nil, err
 	}
-	d, err := db.GetGameInfoByPlatformID("gog", id)
+	d, err := db.GetGameInfoByPlatformID(ctx, "gog", id)
 	if err == nil {
 		d.GameIDs = append(d.GameIDs, game.ID)
 		d.GameIDs = utils.Unique(d.GameIDs)
 		return d, nil
 	}
-	detail, err := GenerateGameInfo("gog", id)
+	detail, err := GenerateGameInfo(ctx, "gog", id)
 	if err != nil {
 		return nil, err
 	}
@@ -1,16 +1,30 @@
 package crawler
 
 import (
+	"GameDB/internal/cache"
+	"GameDB/internal/config"
 	"GameDB/internal/constant"
 	"GameDB/internal/log"
+	"GameDB/internal/model"
 	"GameDB/internal/utils"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
+// ErrHowLongToBeatNotFound is returned when no HowLongToBeat game matches a
+// name. Misses are cached for hltbMissExpire so backfills do not search the
+// same names on every run.
+var ErrHowLongToBeatNotFound = errors.New("Not found")
+
+const hltbMissExpire = 6 * time.Hour
+
 type hltbSearchRequest struct {
 	SearchPage  int      `json:"searchPage"`
 	SearchTerms []string `json:"searchTerms"`
@@ -69,9 +83,9 @@ type hltbSearchData struct {
 	ReleaseWorld    int    `json:"release_world"`
 }
 
-func SearchHowLongToBeat(key string) (*hltbSearchData, error) {
+func SearchHowLongToBeat(ctx context.Context, key string) (*hltbSearchData, error) {
 	log.Logger.Info("Search HowLongToBeat", zap.String("key", key))
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url:    constant.HowLongToBeatSearchURL,
 		Method: "POST",
 		Headers: map[string]string{
@@ -93,22 +107,92 @@ func SearchHowLongToBeat(key string) (*hltbSearchData, error) {
 		log.Logger.Error("Failed to unmarshal JSON", zap.Error(err))
 		return nil, err
 	}
-	maxSim := 0.0
-	maxSimItem := &hltbSearchData{}
+	candidates := make([]*matchCandidate, 0, len(res.Data))
 	for _, item := range res.Data {
-		if item.GameName == key {
-			return &item, nil
+		candidates = append(candidates, &matchCandidate{
+			ID:    item.GameID,
+			Name:  item.GameName,
+			Year:  item.ReleaseWorld,
+			NotPC: item.ProfilePlatform != "" && !strings.Contains(item.ProfilePlatform, "PC"),
+		})
+	}
+	match, err := matchCandidates("howlongtobeat", key, 0, candidates)
+	if err != nil {
+		log.Logger.Warn("Failed to find", zap.String("key", key))
+		return nil, ErrHowLongToBeatNotFound
+	}
+	for i := range res.Data {
+		if res.Data[i].GameID == match.ID {
+			return &res.Data[i], nil
+		}
+	}
+	return nil, ErrHowLongToBeatNotFound
+}
+
+func SearchHowLongToBeatCache(ctx context.Context, key string) (*hltbSearchData, error) {
+	if config.Config.RedisAvaliable {
+		cacheKey := fmt.Sprintf("hltb_search:%s", key)
+		val, exist := cache.Redis.Get(cacheKey)
+		if exist {
+			if val == "null" {
+				return nil, ErrHowLongToBeatNotFound
+			}
+			var data hltbSearchData
+			if err := json.Unmarshal([]byte(val), &data); err != nil {
+				return nil, err
+			}
+			return &data, nil
 		} else {
-			sim := utils.Similarity(key, item.GameName)
-			if sim >= 0.8 && sim > maxSim {
-				maxSim = sim
-				maxSimItem = &item
+			data, err := SearchHowLongToBeat(ctx, key)
+			if errors.Is(err, ErrHowLongToBeatNotFound) {
+				if err := cache.Redis.AddWithExpire(cacheKey, "null", hltbMissExpire); err != nil {
+					log.Logger.Warn("Failed to add cache", zap.Error(err))
+				}
+				return nil, err
 			}
+			if err != nil {
+				return nil, err
+			}
+			dataBytes, err := json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+			err = cache.Redis.Add(cacheKey, dataBytes)
+			if err != nil {
+				log.Logger.Warn("Failed to add cache", zap.Error(err))
+			}
+			return data, nil
 		}
+	} else {
+		return SearchHowLongToBeat(ctx, key)
+	}
+}
+
+// SetHowLongToBeat fills the HowLongToBeat ID and the playtime of a game
+// info by its name.
+func SetHowLongToBeat(ctx context.Context, item *model.GameInfo) error {
+	data, err := SearchHowLongToBeatCache(ctx, item.Name)
+	if err != nil {
+		return err
+	}
+	item.HowLongToBeatID = data.GameID
+	item.Playtime = hltbPlaytime(data)
+	return nil
+}
+
+// hltbPlaytime converts the completion times of HowLongToBeat from seconds
+// to hours rounded to one decimal, nil if there are none.
+func hltbPlaytime(data *hltbSearchData) *model.Playtime {
+	hours := func(seconds int) float64 {
+		return math.Round(float64(seconds)/360) / 10
+	}
+	playtime := &model.Playtime{
+		Main:          hours(data.CompMain),
+		MainExtra:     hours(data.CompPlus),
+		Completionist: hours(data.Comp100),
 	}
-	if maxSim >= 0.8 {
-		return maxSimItem, nil
+	if playtime.Main == 0 && playtime.MainExtra == 0 && playtime.Completionist == 0 {
+		return nil
 	}
-	log.Logger.Warn("Failed to find", zap.String("key", key))
-	return nil, errors.New("Not found")
+	return playtime
 }
@@ -0,0 +1,13 @@
+package crawler
+
+import "testing"
+
+func TestHLTBPlaytime(t *testing.T) {
+	playtime := hltbPlaytime(&hltbSearchData{CompMain: 92385, CompPlus: 185400, Comp100: 0})
+	if playtime == nil || playtime.Main != 25.7 || playtime.MainExtra != 51.5 || playtime.Completionist != 0 {
+		t.Errorf("hltbPlaytime = %+v, want 25.7, 51.5 and 0 hours", playtime)
+	}
+	if playtime := hltbPlaytime(&hltbSearchData{}); playtime != nil {
+		t.Errorf("hltbPlaytime without times = %+v, want nil", playtime)
+	}
+}
@@ -8,21 +8,25 @@ import (
 	"GameDB/internal/log"
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
 
 var TwitchToken string
 
-func _GetIGDBID(name string, prepare bool) (int, error) {
+func _GetIGDBID(ctx context.Context, name string, year int, prepare bool) (*model.MatchResult, error) {
 	if prepare {
 		name = GetIDPrepared(name)
 	}
@@ -31,13 +35,13 @@ func _GetIGDBID(name string, prepare bool) (int, error) {
 	name = regexp.MustCompile(`\s+`).ReplaceAllString(name, " ")
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			log.Logger.Error("Failed to login", zap.Error(err))
-			return 0, err
+			return nil, err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBGameURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -50,62 +54,61 @@ func _GetIGDBID(name string, prepare bool) (int, error) {
 	})
 	if err != nil {
 		log.Logger.Error("Failed to fetch", zap.Error(err))
-		return 0, err
+		return nil, err
 	}
 	var data model.IGDBGameDetails
 	if err = json.Unmarshal(resp.Data, &data); err != nil {
 		log.Logger.Error("Failed to unmarshal", zap.Error(err))
-		return 0, err
-	}
-	if len(data) == 1 {
-		return data[0].ID, nil
+		return nil, err
 	}
-	maxSim := 0.0
-	maxSimID := 0
-	maxSimItem := &model.IGDBGameDetail{}
+	candidates := make([]*matchCandidate, 0, len(data))
 	for _, item := range data {
-		if item.Platforms != nil && len(item.Platforms) > 0 {
-			if !slices.Contains(item.Platforms, 6) &&
-				!slices.Contains(item.Platforms, 130) {
-				continue
-			}
+		c := &matchCandidate{
+			ID:      item.ID,
+			Name:    item.Name,
+			MatchID: item.ParentGame,
+			NotPC:   len(item.Platforms) > 0 && !slices.Contains(item.Platforms, 6) && !slices.Contains(item.Platforms, 130),
 		}
-		if item.Name == name {
-			return item.ID, nil
-		} else {
-			sim := utils.Similarity(item.Name, name)
-			if sim > 0.8 && sim > maxSim {
-				maxSim = sim
-				maxSimID = item.ID
-				maxSimItem = item
-			}
+		if item.FirstReleaseDate != 0 {
+			c.Year = time.Unix(int64(item.FirstReleaseDate), 0).UTC().Year()
 		}
+		candidates = append(candidates, c)
 	}
-	if maxSimID == 0 {
+	res, err := matchCandidates("igdb", name, year, candidates)
+	if err != nil {
 		log.Logger.Error("IGDB ID not found", zap.String("key", name))
-		return 0, errors.New("IGDB ID not found")
+		return res, err
 	}
-	if maxSimItem.ParentGame != 0 {
-		log.Logger.Info("Found IGDB ID", zap.Int("id", maxSimItem.ParentGame), zap.String("key", name))
-		return maxSimItem.ParentGame, nil
+	if !slices.ContainsFunc(data, func(item *model.IGDBGameDetail) bool { return item.ID == res.ID }) {
+		// the parent game of the matched edition was not found by the search
+		detail, err := GetIGDBAppDetailCache(ctx, res.ID)
+		if err != nil {
+			log.Logger.Warn("Failed to get igdb parent game", zap.Int("id", res.ID), zap.Error(err))
+		} else {
+			res.Name = detail.Name
+		}
 	}
-	log.Logger.Info("Found IGDB ID", zap.Int("id", maxSimID), zap.String("key", name))
-	return maxSimID, nil
+	log.Logger.Info("Found IGDB ID", zap.Int("id", res.ID), zap.String("key", name), zap.Float64("confidence", res.Confidence))
+	return res, nil
 }
 
-func GetIGDBID(name string) (int, error) {
-	id, err := _GetIGDBID(name, false)
-	if err == nil {
-		return id, nil
-	}
-	id, err = _GetIGDBID(name, true)
-	if err == nil {
-		return id, nil
+// MatchIGDB matches the name of a game with IGDB, year is the release year of
+// the game or 0 if unknown.
+func MatchIGDB(ctx context.Context, name string, year int) (*model.MatchResult, error) {
+	return matchPrepared(func(prepare bool) (*model.MatchResult, error) {
+		return _GetIGDBID(ctx, name, year, prepare)
+	})
+}
+
+func GetIGDBID(ctx context.Context, name string) (int, error) {
+	res, err := MatchIGDB(ctx, name, 0)
+	if err != nil {
+		return 0, errors.New("IGDB ID not found")
 	}
-	return 0, errors.New("IGDB ID not found")
+	return res.ID, nil
 }
 
-func GetIGDBIDCache(name string) (int, error) {
+func GetIGDBIDCache(ctx context.Context, name string) (int, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("igdb_id:%s", name)
 		val, exist := cache.Redis.Get(key)
@@ -116,7 +119,7 @@ func GetIGDBIDCache(name string) (int, error) {
 			}
 			return id, nil
 		} else {
-			id, err := GetIGDBID(key)
+			id, err := GetIGDBID(ctx, key)
 			if err != nil {
 				return 0, err
 			}
@@ -127,19 +130,19 @@ func GetIGDBIDCache(name string) (int, error) {
 			return id, nil
 		}
 	} else {
-		return GetIGDBID(name)
+		return GetIGDBID(ctx, name)
 	}
 }
 
-func GetIGDBAppDetail(id int) (*model.IGDBGameDetail, error) {
+func GetIGDBAppDetail(ctx context.Context, id int) (*model.IGDBGameDetail, error) {
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBGameURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -161,12 +164,12 @@ func GetIGDBAppDetail(id int) (*model.IGDBGameDetail, error) {
 		return nil, errors.New("IGDB App not found")
 	}
 	if data[0].Name == "" {
-		return GetIGDBAppDetail(id)
+		return GetIGDBAppDetail(ctx, id)
 	}
 	return data[0], nil
 }
 
-func GetIGDBAppDetailCache(id int) (*model.IGDBGameDetail, error) {
+func GetIGDBAppDetailCache(ctx context.Context, id int) (*model.IGDBGameDetail, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("igdb_game:%v", id)
 		val, exist := cache.Redis.Get(key)
@@ -177,7 +180,7 @@ func GetIGDBAppDetailCache(id int) (*model.IGDBGameDetail, error) {
 			}
 			return &data, nil
 		} else {
-			data, err := GetIGDBAppDetail(id)
+			data, err := GetIGDBAppDetail(ctx, id)
 			if err != nil {
 				return nil, err
 			}
@@ -193,19 +196,19 @@ func GetIGDBAppDetailCache(id int) (*model.IGDBGameDetail, error) {
 			return data, nil
 		}
 	} else {
-		return GetIGDBAppDetail(id)
+		return GetIGDBAppDetail(ctx, id)
 	}
 }
 
-func GetIGDBScreenshots(game int) ([]string, error) {
+func GetIGDBScreenshots(ctx context.Context, game int) ([]string, error) {
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBScreenshotsURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -231,12 +234,12 @@ func GetIGDBScreenshots(game int) ([]string, error) {
 		return nil, errors.New("screenshots not found")
 	}
 	if screenshots[0] == "" {
-		return GetIGDBScreenshots(game) // server sometimes return wrong data, just contains id
+		return GetIGDBScreenshots(ctx, game) // server sometimes return wrong data, just contains id
 	}
 	return screenshots, nil
 }
 
-func GetIGDBScreenshotsCache(game int) ([]string, error) {
+func GetIGDBScreenshotsCache(ctx context.Context, game int) ([]string, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("igdb_screenshots:%v", game)
 		val, exist := cache.Redis.Get(key)
@@ -247,7 +250,7 @@ func GetIGDBScreenshotsCache(game int) ([]string, error) {
 			}
 			return data, nil
 		} else {
-			data, err := GetIGDBScreenshots(game)
+			data, err := GetIGDBScreenshots(ctx, game)
 			if err != nil {
 				return nil, err
 			}
@@ -263,19 +266,19 @@ func GetIGDBScreenshotsCache(game int) ([]string, error) {
 			return data, nil
 		}
 	} else {
-		return GetIGDBScreenshots(game)
+		return GetIGDBScreenshots(ctx, game)
 	}
 }
 
-func GetIGDBCovers(game int) (string, error) {
+func GetIGDBCovers(ctx context.Context, game int) (string, error) {
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			return "", err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBCoversURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -300,19 +303,19 @@ func GetIGDBCovers(game int) (string, error) {
 		return "", errors.New("cover not found")
 	}
 	if data[0].URL == "" {
-		return GetIGDBCovers(game)
+		return GetIGDBCovers(ctx, game)
 	}
 	return "", errors.New("cover not found")
 }
 
-func GetIGDBCoversCache(game int) (string, error) {
+func GetIGDBCoversCache(ctx context.Context, game int) (string, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("igdb_covers:%v", game)
 		val, exist := cache.Redis.Get(key)
 		if exist {
 			return val, nil
 		} else {
-			val, err := GetIGDBCovers(game)
+			val, err := GetIGDBCovers(ctx, game)
 			if err != nil {
 				return "", err
 			}
@@ -323,19 +326,19 @@ func GetIGDBCoversCache(game int) (string, error) {
 			return val, nil
 		}
 	} else {
-		return GetIGDBCovers(game)
+		return GetIGDBCovers(ctx, game)
 	}
 }
 
-func GetIGDBLanguagesID(game int) ([]*model.Language, error) {
+func GetIGDBLanguagesID(ctx context.Context, game int) ([]*model.Language, error) {
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBLanguageSupportsURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -358,16 +361,16 @@ func GetIGDBLanguagesID(game int) ([]*model.Language, error) {
 		return []*model.Language{}, nil
 	}
 	if data[0].Language == 0 {
-		return GetIGDBLanguagesID(game)
+		return GetIGDBLanguagesID(ctx, game)
 	}
 	for _, item := range data {
 		languages = append(languages, item.Language)
 	}
 	languages = utils.Unique(languages)
-	return db.GetLanguages(languages)
+	return db.GetLanguages(ctx, languages)
 }
 
-func GetIGDBLanguagesIDCache(game int) ([]*model.Language, error) {
+func GetIGDBLanguagesIDCache(ctx context.Context, game int) ([]*model.Language, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("igdb_languages:%v", game)
 		val, exist := cache.Redis.Get(key)
@@ -378,7 +381,7 @@ func GetIGDBLanguagesIDCache(game int) ([]*model.Language, error) {
 			}
 			return data, nil
 		} else {
-			val, err := GetIGDBLanguagesID(game)
+			val, err := GetIGDBLanguagesID(ctx, game)
 			if err != nil {
 				return nil, err
 			}
@@ -393,19 +396,19 @@ func GetIGDBLanguagesIDCache(game int) ([]*model.Language, error) {
 			return val, nil
 		}
 	} else {
-		return GetIGDBLanguagesID(game)
+		return GetIGDBLanguagesID(ctx, game)
 	}
 }
 
-func GetIGDBAliases(game int) ([]string, error) {
+func GetIGDBAliases(ctx context.Context, game int) ([]string, error) {
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBAlternativeNamesURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -432,12 +435,12 @@ func GetIGDBAliases(game int) ([]string, error) {
 	}
 	utils.Unique(aliases)
 	if aliases[0] == "" {
-		return GetIGDBAliases(game)
+		return GetIGDBAliases(ctx, game)
 	}
 	return aliases, nil
 }
 
-func GetIGDBAliasesCache(game int) ([]string, error) {
+func GetIGDBAliasesCache(ctx context.Context, game int) ([]string, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("igdb_aliases:%v", game)
 		val, exist := cache.Redis.Get(key)
@@ -449,7 +452,7 @@ func GetIGDBAliasesCache(game int) ([]string, error) {
 			}
 			return data, nil
 		} else {
-			data, err := GetIGDBAliases(game)
+			data, err := GetIGDBAliases(ctx, game)
 			if err != nil {
 				return nil, err
 			}
@@ -465,19 +468,19 @@ func GetIGDBAliasesCache(game int) ([]string, error) {
 			return data, nil
 		}
 	} else {
-		return GetIGDBAliases(game)
+		return GetIGDBAliases(ctx, game)
 	}
 }
 
-func SaveIGDBLanguagesList() error {
+func SaveIGDBLanguagesList(ctx context.Context) error {
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			return err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBLanguagesURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -496,7 +499,7 @@ func SaveIGDBLanguagesList() error {
 		return err
 	}
 	for _, item := range data {
-		err = db.SaveLanguage(&model.Language{
+		err = db.SaveLanguage(ctx, &model.Language{
 			LID:        item.ID,
 			Name:       item.Name,
 			NativeName: item.NativeName,
@@ -508,14 +511,14 @@ func SaveIGDBLanguagesList() error {
 	return nil
 }
 
-func LoginTwitch() (string, error) {
+func LoginTwitch(ctx context.Context) (string, error) {
 	baseURL, _ := url.Parse(constant.TwitchAuthURL)
 	params := url.Values{}
 	params.Add("client_id", config.Config.Twitch.ClientID)
 	params.Add("client_secret", config.Config.Twitch.ClientSecret)
 	params.Add("grant_type", "client_credentials")
 	baseURL.RawQuery = params.Encode()
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url:    baseURL.String(),
 		Method: "POST",
 		Headers: map[string]string{
@@ -535,15 +538,15 @@ func LoginTwitch() (string, error) {
 	return data.AccessToken, nil
 }
 
-func GetIGDBInvolvedCompanies(game int) (model.IGDBInvolvedCompanies, error) {
+func GetIGDBInvolvedCompanies(ctx context.Context, game int) (model.IGDBInvolvedCompanies, error) {
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBInvolvedCompaniesURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -565,12 +568,12 @@ func GetIGDBInvolvedCompanies(game int) (model.IGDBInvolvedCompanies, error) {
 		return nil, errors.New("no data found")
 	}
 	if data[0].Company == 0 {
-		return GetIGDBInvolvedCompanies(game)
+		return GetIGDBInvolvedCompanies(ctx, game)
 	}
 	return data, nil
 }
 
-func GetIGDBInvolvedCompaniesCache(game int) (model.IGDBInvolvedCompanies, error) {
+func GetIGDBInvolvedCompaniesCache(ctx context.Context, game int) (model.IGDBInvolvedCompanies, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("igdb_involved_companies:%v", game)
 		val, exist := cache.Redis.Get(key)
@@ -582,7 +585,7 @@ func GetIGDBInvolvedCompaniesCache(game int) (model.IGDBInvolvedCompanies, error
 			}
 			return data, nil
 		} else {
-			data, err := GetIGDBInvolvedCompanies(game)
+			data, err := GetIGDBInvolvedCompanies(ctx, game)
 			if err != nil {
 				return nil, err
 			}
@@ -598,19 +601,19 @@ func GetIGDBInvolvedCompaniesCache(game int) (model.IGDBInvolvedCompanies, error
 			return data, nil
 		}
 	} else {
-		return GetIGDBInvolvedCompanies(game)
+		return GetIGDBInvolvedCompanies(ctx, game)
 	}
 }
 
-func GetIGDBCompany(id int) (string, error) {
+func GetIGDBCompany(ctx context.Context, id int) (string, error) {
 	var err error
 	if TwitchToken == "" {
-		TwitchToken, err = LoginTwitch()
+		TwitchToken, err = LoginTwitch(ctx)
 		if err != nil {
 			return "", err
 		}
 	}
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.IGDBCompaniesURL,
 		Headers: map[string]string{
 			"Client-ID":     config.Config.Twitch.ClientID,
@@ -632,19 +635,19 @@ func GetIGDBCompany(id int) (string, error) {
 		return "", errors.New("Not found")
 	}
 	if data[0].Name == "" {
-		return GetIGDBCompany(id)
+		return GetIGDBCompany(ctx, id)
 	}
 	return data[0].Name, nil
 }
 
-func GetIGDBCompanyCache(id int) (string, error) {
+func GetIGDBCompanyCache(ctx context.Context, id int) (string, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("igdb_companies:%v", id)
 		val, exist := cache.Redis.Get(key)
 		if exist {
 			return val, nil
 		} else {
-			data, err := GetIGDBCompany(id)
+			data, err := GetIGDBCompany(ctx, id)
 			if err != nil {
 				return "", err
 			}
@@ -656,13 +659,265 @@ func GetIGDBCompanyCache(id int) (string, error) {
 			return data, nil
 		}
 	} else {
-		return GetIGDBCompany(id)
+		return GetIGDBCompany(ctx, id)
 	}
 }
 
-func GenerateIGDBGameInfo(id int) (*model.GameInfo, error) {
+// igdbExternalGameStores maps the categories of IGDB external games to the
+// keys of GameInfo.StoreIDs, Steam and GOG have their own fields.
+var igdbExternalGameStores = map[int]string{
+	11: "microsoft",
+	13: "apple",
+	15: "android",
+	20: "amazon",
+	26: "epic",
+	28: "oculus",
+	30: "itch",
+	31: "xbox",
+	36: "playstation",
+	55: "gamejolt",
+}
+
+const (
+	igdbExternalGameSteam = 1
+	igdbExternalGameGOG   = 5
+)
+
+func GetIGDBExternalGames(ctx context.Context, game int) (model.IGDBExternalGames, error) {
+	// IGDB sometimes answers without the store of the external games, the
+	// request is retried once
+	for retry := 0; ; retry++ {
+		data, err := getIGDBExternalGames(ctx, game)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 || data[0].Source() != 0 {
+			return data, nil
+		}
+		if retry == 1 {
+			return nil, fmt.Errorf("igdb external games of %v have no store", game)
+		}
+	}
+}
+
+func getIGDBExternalGames(ctx context.Context, game int) (model.IGDBExternalGames, error) {
+	var err error
+	if TwitchToken == "" {
+		TwitchToken, err = LoginTwitch(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url: constant.IGDBExternalGamesURL,
+		Headers: map[string]string{
+			"Client-ID":     config.Config.Twitch.ClientID,
+			"Authorization": "Bearer " + TwitchToken,
+			"User-Agent":    "",
+			"Content-Type":  "text/plain",
+		},
+		Data:   fmt.Sprintf(`where game=%v; fields *; limit 100;`, game),
+		Method: "POST",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var data model.IGDBExternalGames
+	if err = json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func GetIGDBExternalGamesCache(ctx context.Context, game int) (model.IGDBExternalGames, error) {
+	if config.Config.RedisAvaliable {
+		key := fmt.Sprintf("igdb_external_games:%v", game)
+		val, exist := cache.Redis.Get(key)
+		if exist {
+			var data model.IGDBExternalGames
+			if err := json.Unmarshal([]byte(val), &data); err != nil {
+				return nil, err
+			}
+			return data, nil
+		} else {
+			data, err := GetIGDBExternalGames(ctx, game)
+			if err != nil {
+				return nil, err
+			}
+			dataBytes, err := json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+			err = cache.Redis.Add(key, dataBytes)
+			if err != nil {
+				log.Logger.Warn("Failed to add cache", zap.Error(err))
+			}
+			return data, nil
+		}
+	} else {
+		return GetIGDBExternalGames(ctx, game)
+	}
+}
+
+// SetIGDBExternalIDs fills the Steam, GOG and other store IDs of a game info
+// from the external games of its IGDB ID. IDs the game info already has are
+// kept. Returns whether any ID was added.
+func SetIGDBExternalIDs(ctx context.Context, item *model.GameInfo) (bool, error) {
+	if item.IGDBID == 0 {
+		return false, nil
+	}
+	externals, err := GetIGDBExternalGamesCache(ctx, item.IGDBID)
+	if err != nil {
+		return false, err
+	}
+	return setExternalIDs(item, externals), nil
+}
+
+func setExternalIDs(item *model.GameInfo, externals model.IGDBExternalGames) bool {
+	changed := false
+	for _, external := range externals {
+		if external.UID == "" {
+			continue
+		}
+		source := external.Source()
+		switch source {
+		case igdbExternalGameSteam, igdbExternalGameGOG:
+			id, err := strconv.Atoi(external.UID)
+			if err != nil || id <= 0 {
+				continue
+			}
+			field := &item.SteamID
+			if source == igdbExternalGameGOG {
+				field = &item.GOGID
+			}
+			if *field == 0 {
+				*field = id
+				changed = true
+			}
+		default:
+			store, ok := igdbExternalGameStores[source]
+			if !ok {
+				continue
+			}
+			if _, exist := item.StoreIDs[store]; exist {
+				continue
+			}
+			if item.StoreIDs == nil {
+				item.StoreIDs = make(map[string]string)
+			}
+			item.StoreIDs[store] = external.UID
+			changed = true
+		}
+	}
+	return changed
+}
+
+// GetIGDBReferenceNames returns the names of the IGDB references ids, e.g.
+// genres, in the order of ids. Names missing in the reference collection are
+// fetched from the IGDB endpoint and stored there.
+func GetIGDBReferenceNames(ctx context.Context, collection *mongo.Collection, endpoint string, ids []int) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	references, err := db.GetReferences(ctx, collection, ids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(ids))
+	for _, reference := range references {
+		names[reference.RID] = reference.Name
+	}
+	var missing []string
+	for _, id := range ids {
+		if _, ok := names[id]; !ok {
+			missing = append(missing, strconv.Itoa(id))
+		}
+	}
+	if len(missing) > 0 {
+		fetched, err := GetIGDBReferences(ctx, endpoint, missing)
+		if err != nil {
+			return nil, err
+		}
+		references = references[:0]
+		for _, item := range fetched {
+			names[item.ID] = item.Name
+			references = append(references, &model.Reference{RID: item.ID, Name: item.Name})
+		}
+		if err := db.SaveReferences(ctx, collection, references); err != nil {
+			log.Logger.Warn("Failed to save igdb references", zap.String("collection", collection.Name()), zap.Error(err))
+		}
+	}
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if name := names[id]; name != "" {
+			res = append(res, name)
+		}
+	}
+	return res, nil
+}
+
+func GetIGDBReferences(ctx context.Context, endpoint string, ids []string) (model.IGDBReferences, error) {
+	var err error
+	if TwitchToken == "" {
+		TwitchToken, err = LoginTwitch(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url: endpoint,
+		Headers: map[string]string{
+			"Client-ID":     config.Config.Twitch.ClientID,
+			"Authorization": "Bearer " + TwitchToken,
+			"User-Agent":    "",
+			"Content-Type":  "text/plain",
+		},
+		Data:   fmt.Sprintf(`where id=(%s); fields name; limit 500;`, strings.Join(ids, ",")),
+		Method: "POST",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var data model.IGDBReferences
+	if err = json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// SetIGDBAttributes fills the genres, themes, game modes, perspectives,
+// franchises, release date and rating of a game info from its IGDB detail.
+func SetIGDBAttributes(ctx context.Context, item *model.GameInfo, detail *model.IGDBGameDetail) {
+	for _, reference := range []struct {
+		collection *mongo.Collection
+		endpoint   string
+		ids        []int
+		names      *[]string
+	}{
+		{db.GenreCollection, constant.IGDBGenresURL, detail.Genres, &item.Genres},
+		{db.ThemeCollection, constant.IGDBThemesURL, detail.Themes, &item.Themes},
+		{db.GameModeCollection, constant.IGDBGameModesURL, detail.GameModes, &item.GameModes},
+		{db.PerspectiveCollection, constant.IGDBPerspectivesURL, detail.PlayerPerspectives, &item.Perspectives},
+		{db.FranchiseCollection, constant.IGDBFranchisesURL, detail.Franchises, &item.Franchises},
+	} {
+		names, err := GetIGDBReferenceNames(ctx, reference.collection, reference.endpoint, reference.ids)
+		if err != nil {
+			log.Logger.Warn("Failed to get igdb references", zap.String("collection", reference.collection.Name()), zap.Error(err))
+			continue
+		}
+		*reference.names = names
+	}
+	if detail.FirstReleaseDate != 0 {
+		releaseDate := time.Unix(int64(detail.FirstReleaseDate), 0).UTC()
+		item.ReleaseDate = &releaseDate
+	}
+	item.Rating = math.Round(detail.TotalRating*10) / 10
+	item.RatingCount = detail.TotalRatingCount
+}
+
+func GenerateIGDBGameInfo(ctx context.Context, id int) (*model.GameInfo, error) {
 	item := &model.GameInfo{}
-	detail, err := GetIGDBAppDetailCache(id)
+	detail, err := GetIGDBAppDetailCache(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -670,7 +925,7 @@ func GenerateIGDBGameInfo(id int) (*model.GameInfo, error) {
 	item.Name = detail.Name
 	item.Description = detail.Summary
 
-	languages, err := GetIGDBLanguagesIDCache(id)
+	languages, err := GetIGDBLanguagesIDCache(ctx, id)
 	if err != nil {
 		log.Logger.Warn("Failed to get igdb languages", zap.Error(err))
 	}
@@ -679,7 +934,7 @@ func GenerateIGDBGameInfo(id int) (*model.GameInfo, error) {
 	}
 
 	if len(detail.Screenshots) > 0 {
-		screenshots, err := GetIGDBScreenshotsCache(id)
+		screenshots, err := GetIGDBScreenshotsCache(ctx, id)
 		if err != nil {
 			log.Logger.Warn("Failed to get igdb screenshots", zap.Error(err))
 		}
@@ -687,26 +942,34 @@ func GenerateIGDBGameInfo(id int) (*model.GameInfo, error) {
 	}
 
 	if len(detail.AlternativeNames) > 0 {
-		aliases, err := GetIGDBAliasesCache(detail.ID)
+		aliases, err := GetIGDBAliasesCache(ctx, detail.ID)
 		if err != nil {
 			log.Logger.Warn("Failed to get igdb aliases", zap.Error(err))
 		}
 		item.Aliases = append(item.Aliases, aliases...)
 	}
 
-	item.Cover, err = GetIGDBCoversCache(id)
+	if len(detail.ExternalGames) > 0 {
+		if _, err := SetIGDBExternalIDs(ctx, item); err != nil {
+			log.Logger.Warn("Failed to get igdb external games", zap.Error(err))
+		}
+	}
+
+	SetIGDBAttributes(ctx, item, detail)
+
+	item.Cover, err = GetIGDBCoversCache(ctx, id)
 	if err != nil {
 		log.Logger.Warn("Failed to get igdb cover", zap.Error(err))
 	}
 
 	if len(detail.InvolvedCompanies) > 0 {
-		involvedCompanies, err := GetIGDBInvolvedCompaniesCache(id)
+		involvedCompanies, err := GetIGDBInvolvedCompaniesCache(ctx, id)
 		if err != nil {
 			log.Logger.Warn("Failed to get igdb involved companies", zap.Error(err))
 		} else {
 			for _, company := range involvedCompanies {
 				if company.Developer || company.Publisher {
-					companyName, err := GetIGDBCompanyCache(company.Company)
+					companyName, err := GetIGDBCompanyCache(ctx, company.Company)
 					if err != nil {
 						log.Logger.Warn("Failed to get igdb company", zap.Error(err))
 						continue
@@ -724,18 +987,18 @@ func GenerateIGDBGameInfo(id int) (*model.GameInfo, error) {
 	return item, nil
 }
 
-func ProcessGameWithIGDB(game *model.GameDownload) (*model.GameInfo, error) {
-	id, err := GetIGDBID(game.Name)
+func ProcessGameWithIGDB(ctx context.Context, game *model.GameDownload, overrides *MatchOverrides) (*model.GameInfo, error) {
+	id, err := matchGame(ctx, game, overrides, "igdb", MatchIGDB)
 	if err != nil {
 		return nil, err
 	}
-	d, err := db.GetGameInfoByPlatformID("igdb", id)
+	d, err := db.GetGameInfoByPlatformID(ctx, "igdb", id)
 	if err == nil {
 		d.GameIDs = append(d.GameIDs, game.ID)
 		d.GameIDs = utils.Unique(d.GameIDs)
 		return d, nil
 	}
-	info, err := GenerateGameInfo("igdb", id)
+	info, err := GenerateGameInfo(ctx, "igdb", id)
 	if err != nil {
 		return nil, err
 	}
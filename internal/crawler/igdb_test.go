@@ -0,0 +1,30 @@
+package crawler
+
+import (
+	"GameDB/internal/model"
+	"testing"
+)
+
+func TestSetExternalIDs(t *testing.T) {
+	item := &model.GameInfo{GOGID: 1}
+	externals := model.IGDBExternalGames{
+		{Category: igdbExternalGameSteam, UID: "292030"},
+		{Category: igdbExternalGameSteam, UID: "355880"},
+		{Category: igdbExternalGameGOG, UID: "1207664663"},
+		{ExternalGameSource: 26, UID: "witcher3"},
+		{Category: 10, UID: "youtube"},
+		{Category: igdbExternalGameSteam, UID: ""},
+	}
+	if !setExternalIDs(item, externals) {
+		t.Fatal("setExternalIDs = false, want true")
+	}
+	if item.SteamID != 292030 || item.GOGID != 1 {
+		t.Errorf("steam %d gog %d, want 292030 and the kept 1", item.SteamID, item.GOGID)
+	}
+	if len(item.StoreIDs) != 1 || item.StoreIDs["epic"] != "witcher3" {
+		t.Errorf("store ids = %v", item.StoreIDs)
+	}
+	if setExternalIDs(item, externals) {
+		t.Error("setExternalIDs of known IDs = true, want false")
+	}
+}
@@ -1,19 +1,14 @@
 package crawler
 
 import (
-	"GameDB/internal/model"
 	"regexp"
 	"strings"
 )
 
 const KaOsKrewName string = "KaOsKrew-torrents"
 
-func CrawlKaOsKrewMulti(pages []int) ([]*model.GameDownload, error) {
-	return Crawl1337xMulti(DODIName, pages, DODIFormatter)
-}
-
-func CrawlKaOsKrewAll() ([]*model.GameDownload, error) {
-	return Crawl1337xAll(DODIName, DODIFormatter)
+func init() {
+	RegisterSource(&c1337xSource{name: "kaoskrew", user: KaOsKrewName, formatter: KaOsKrewFormatter})
 }
 
 var kaOsKrewRegexps = []*regexp.Regexp{
@@ -31,6 +26,6 @@ func KaOsKrewFormatter(name string) string {
 		name = re.ReplaceAllString(name, "")
 	}
 	name = strings.Replace(name, ".", " ", -1)
-	name = regexp.MustCompile(`(?i)\sgoty`).ReplaceAllString(": Game Of The Year", name)
+	name = regexp.MustCompile(`(?i)\sgoty`).ReplaceAllString(name, ": Game Of The Year")
 	return strings.TrimSpace(name)
 }
@@ -0,0 +1,70 @@
+package crawler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/model"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrGameInfoNotFound = errors.New("game info not found")
+
+// UnlinkGameDownload removes a game download from the game info infoID, or
+// from all its game infos if infoID is nil. A game download left in no game
+// info is matched again by organize, unless it was marked as not a game.
+// Returns whether it was left orphaned.
+func UnlinkGameDownload(ctx context.Context, gameID primitive.ObjectID, infoID primitive.ObjectID) (bool, error) {
+	if _, err := reviewedGameDownload(ctx, gameID); err != nil {
+		return false, err
+	}
+	if err := db.UnlinkGameDownloads(ctx, []primitive.ObjectID{gameID}, infoID); err != nil {
+		return false, err
+	}
+	linked, err := db.IsGameDownloadLinked(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+	if linked {
+		return false, nil
+	}
+	return true, db.ResetGameDownloadMatch(ctx, gameID)
+}
+
+// MoveGameDownload removes a game download from the game info from, or from
+// all its game infos if from is nil, and adds it to the game info to. The
+// move is recorded as an accepted review.
+func MoveGameDownload(ctx context.Context, gameID primitive.ObjectID, from primitive.ObjectID, to primitive.ObjectID) error {
+	if _, err := reviewedGameDownload(ctx, gameID); err != nil {
+		return err
+	}
+	info, err := db.GetGameInfoByID(ctx, to)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrGameInfoNotFound
+		}
+		return err
+	}
+	if err := db.UnlinkGameDownloads(ctx, []primitive.ObjectID{gameID}, from); err != nil {
+		return err
+	}
+	linked, err := db.LinkGameDownload(ctx, gameID, to)
+	if err != nil {
+		return err
+	}
+	if !linked {
+		return ErrGameInfoNotFound
+	}
+	review := &model.Review{Status: model.ReviewStatusAccepted}
+	switch {
+	case info.IGDBID != 0:
+		review.IDType, review.ID = "igdb", info.IGDBID
+	case info.SteamID != 0:
+		review.IDType, review.ID = "steam", info.SteamID
+	case info.GOGID != 0:
+		review.IDType, review.ID = "gog", info.GOGID
+	}
+	return db.SaveGameDownloadReview(ctx, gameID, review)
+}
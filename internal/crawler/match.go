@@ -0,0 +1,121 @@
+package crawler
+
+import (
+	"GameDB/internal/model"
+	"GameDB/internal/utils"
+	"errors"
+	"sort"
+	"time"
+)
+
+const (
+	// MinMatchConfidence is the lowest score of a candidate that is matched
+	MinMatchConfidence = 0.7
+	matchCandidatesNum = 5
+	// matchPlatformPenalty is subtracted from candidates not released on PC
+	matchPlatformPenalty = 0.5
+)
+
+var ErrNoMatch = errors.New("no candidate matched")
+
+type matchCandidate struct {
+	ID   int
+	Name string
+	Year int
+	// NotPC is set if the candidate is known not to be released on PC
+	NotPC bool
+	// MatchID is recorded instead of ID if the candidate is chosen, e.g. the
+	// parent game of an edition
+	MatchID int
+}
+
+// matchCandidates scores candidates against the name of a game download and
+// chooses the best one if its score reaches MinMatchConfidence, otherwise
+// ErrNoMatch is returned with the result. The result keeps the best
+// matchCandidatesNum candidates.
+func matchCandidates(source string, query string, year int, candidates []*matchCandidate) (*model.MatchResult, error) {
+	scored := make([]model.MatchCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		score := utils.MatchScore(query, c.Name, year, c.Year)
+		if c.NotPC {
+			score = max(0, score-matchPlatformPenalty)
+		}
+		scored = append(scored, model.MatchCandidate{ID: c.ID, MatchID: c.MatchID, Name: c.Name, Year: c.Year, Score: score})
+	}
+	order := make([]int, len(scored))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scored[order[i]].Score > scored[order[j]].Score })
+	res := &model.MatchResult{
+		Source:    source,
+		Query:     query,
+		MatchedAt: time.Now(),
+	}
+	for _, i := range order[:min(len(order), matchCandidatesNum)] {
+		res.Candidates = append(res.Candidates, scored[i])
+	}
+	if len(order) == 0 || scored[order[0]].Score < MinMatchConfidence {
+		return res, ErrNoMatch
+	}
+	best := &scored[order[0]]
+	res.ID = best.LinkID()
+	res.Name = best.Name
+	// an edition is matched as its parent game, name the result after the
+	// parent if it is a candidate too
+	for _, c := range candidates {
+		if best.MatchID != 0 && c.ID == best.MatchID {
+			res.Name = c.Name
+			break
+		}
+	}
+	res.Confidence = scored[order[0]].Score
+	return res, nil
+}
+
+// matchPrepared matches a name as it is and, if no candidate matched, prepared
+// by GetIDPrepared. If both fail the result with the best candidate is
+// returned with the error.
+func matchPrepared(match func(prepare bool) (*model.MatchResult, error)) (*model.MatchResult, error) {
+	res, err := match(false)
+	if err == nil {
+		return res, nil
+	}
+	prepared, preparedErr := match(true)
+	if preparedErr == nil {
+		return prepared, nil
+	}
+	if betterMatch(prepared, res) {
+		return prepared, preparedErr
+	}
+	return res, err
+}
+
+// betterMatch reports whether a is a better match than b, a result with an
+// ID is better than one without, otherwise the best candidate decides.
+func betterMatch(a, b *model.MatchResult) bool {
+	if a == nil {
+		return false
+	}
+	if b == nil {
+		return true
+	}
+	if (a.ID != 0) != (b.ID != 0) {
+		return a.ID != 0
+	}
+	return topScore(a) > topScore(b)
+}
+
+func topScore(res *model.MatchResult) float64 {
+	if len(res.Candidates) == 0 {
+		return 0
+	}
+	return res.Candidates[0].Score
+}
+
+// recordMatch keeps res on game unless game already has a better match.
+func recordMatch(game *model.GameDownload, res *model.MatchResult) {
+	if res != nil && (res.ID != 0 || !betterMatch(game.Match, res)) {
+		game.Match = res
+	}
+}
@@ -0,0 +1,33 @@
+package crawler
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatchCandidates(t *testing.T) {
+	candidates := []*matchCandidate{
+		{ID: 1, Name: "Prey", Year: 2006},
+		{ID: 2, Name: "Prey", Year: 2017, MatchID: 20},
+		{ID: 3, Name: "Prey Digital Deluxe Edition", Year: 2017},
+		{ID: 4, Name: "Prey", Year: 2017, NotPC: true},
+		{ID: 5, Name: "Prey: Mooncrash", Year: 2018},
+		{ID: 6, Name: "Prey 2", Year: 2012},
+		{ID: 20, Name: "Prey (2017)", Year: 2017},
+	}
+	res, err := matchCandidates("igdb", "Prey", 2017, candidates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != 20 || res.Name != "Prey (2017)" || res.Confidence != 1 {
+		t.Errorf("matched %d %q with confidence %.2f, want 20 \"Prey (2017)\" with 1", res.ID, res.Name, res.Confidence)
+	}
+	if len(res.Candidates) != matchCandidatesNum || res.Candidates[0].ID != 2 || res.Candidates[0].LinkID() != 20 {
+		t.Errorf("candidates = %+v", res.Candidates)
+	}
+
+	res, err = matchCandidates("igdb", "Stardew Valley", 0, candidates)
+	if !errors.Is(err, ErrNoMatch) || res.ID != 0 || len(res.Candidates) == 0 {
+		t.Errorf("matchCandidates of an unknown game = %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,276 @@
+package crawler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"GameDB/internal/utils"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// MergeGameInfos merges the game infos in drop into the game info keep and
+// deletes them. If a game info of drop has another IGDB, Steam, GOG or
+// HowLongToBeat ID than keep, nothing is merged and an error listing the ID
+// types is returned, unless force is set: then the IDs of keep win.
+func MergeGameInfos(ctx context.Context, keepID primitive.ObjectID, drop []primitive.ObjectID, force bool) (*model.GameInfo, error) {
+	infos, err := db.GetGameInfosByIDs(ctx, append([]primitive.ObjectID{keepID}, drop...))
+	if err != nil {
+		return nil, err
+	}
+	var keep *model.GameInfo
+	dropped := make([]*model.GameInfo, 0, len(drop))
+	for _, info := range infos {
+		if info.ID == keepID {
+			keep = info
+		} else {
+			dropped = append(dropped, info)
+		}
+	}
+	if keep == nil || len(dropped) != len(drop) {
+		return nil, ErrGameInfoNotFound
+	}
+	for _, info := range dropped {
+		conflicts := mergeGameInfo(keep, info)
+		if len(conflicts) == 0 {
+			continue
+		}
+		if !force {
+			return nil, fmt.Errorf("game info %s has other %s ids than %s", info.ID.Hex(), strings.Join(conflicts, ", "), keep.ID.Hex())
+		}
+		log.Logger.Warn("Merged game infos with different IDs",
+			zap.String("keep", keep.ID.Hex()),
+			zap.String("drop", info.ID.Hex()),
+			zap.Strings("id_types", conflicts),
+		)
+	}
+	if err := db.MergeGameInfos(ctx, keep, drop); err != nil {
+		return nil, err
+	}
+	return keep, nil
+}
+
+// mergeGameInfo adds the downloads, aliases, screenshots and other lists of
+// drop to keep and fills the fields keep is missing. Returns the ID types
+// both have different IDs for.
+func mergeGameInfo(keep *model.GameInfo, drop *model.GameInfo) []string {
+	var conflicts []string
+	mergeID := func(idtype string, keepID *int, dropID int) {
+		if *keepID == 0 {
+			*keepID = dropID
+		} else if dropID != 0 && dropID != *keepID {
+			conflicts = append(conflicts, idtype)
+		}
+	}
+	mergeID("igdb", &keep.IGDBID, drop.IGDBID)
+	mergeID("steam", &keep.SteamID, drop.SteamID)
+	mergeID("gog", &keep.GOGID, drop.GOGID)
+	mergeID("hltb", &keep.HowLongToBeatID, drop.HowLongToBeatID)
+	for _, id := range drop.GameIDs {
+		if !slices.Contains(keep.GameIDs, id) {
+			keep.GameIDs = append(keep.GameIDs, id)
+		}
+	}
+	aliases := append([]string{drop.Name}, drop.Aliases...)
+	for _, alias := range aliases {
+		if alias != "" && !strings.EqualFold(alias, keep.Name) && !slices.ContainsFunc(keep.Aliases, func(a string) bool {
+			return strings.EqualFold(a, alias)
+		}) {
+			keep.Aliases = append(keep.Aliases, alias)
+		}
+	}
+	keep.Screenshots = mergeStrings(keep.Screenshots, drop.Screenshots)
+	keep.Languages = mergeStrings(keep.Languages, drop.Languages)
+	keep.Developers = mergeStrings(keep.Developers, drop.Developers)
+	keep.Publishers = mergeStrings(keep.Publishers, drop.Publishers)
+	if keep.Name == "" {
+		keep.Name = drop.Name
+	}
+	if keep.Description == "" {
+		keep.Description = drop.Description
+	}
+	if keep.Cover == "" {
+		keep.Cover = drop.Cover
+	}
+	if keep.Playtime == nil {
+		keep.Playtime = drop.Playtime
+	}
+	keep.Genres = mergeStrings(keep.Genres, drop.Genres)
+	keep.Themes = mergeStrings(keep.Themes, drop.Themes)
+	keep.GameModes = mergeStrings(keep.GameModes, drop.GameModes)
+	keep.Perspectives = mergeStrings(keep.Perspectives, drop.Perspectives)
+	keep.Franchises = mergeStrings(keep.Franchises, drop.Franchises)
+	if keep.ReleaseDate == nil {
+		keep.ReleaseDate = drop.ReleaseDate
+	}
+	if keep.RatingCount == 0 {
+		keep.Rating = drop.Rating
+		keep.RatingCount = drop.RatingCount
+	}
+	if keep.Steam == nil {
+		keep.Steam = drop.Steam
+	}
+	for store, id := range drop.StoreIDs {
+		if _, exist := keep.StoreIDs[store]; !exist {
+			if keep.StoreIDs == nil {
+				keep.StoreIDs = make(map[string]string)
+			}
+			keep.StoreIDs[store] = id
+		}
+	}
+	if !drop.CreatedAt.IsZero() && (keep.CreatedAt.IsZero() || drop.CreatedAt.Before(keep.CreatedAt)) {
+		keep.CreatedAt = drop.CreatedAt
+	}
+	return conflicts
+}
+
+func mergeStrings(a []string, b []string) []string {
+	for _, s := range b {
+		if !slices.Contains(a, s) {
+			a = append(a, s)
+		}
+	}
+	return a
+}
+
+// DuplicateGroup is a group of game infos found by FindDuplicateGameInfos.
+// Certain groups are linked by IDs, or by names of game infos both released
+// in the same known year, and can be merged without a look.
+type DuplicateGroup struct {
+	Infos   []*model.GameInfo
+	Certain bool
+}
+
+// FindDuplicateGameInfos groups game infos sharing an IGDB, Steam or GOG ID,
+// a normalized name or an alias. Game infos with different IDs of the same
+// type are never grouped, nor are game infos sharing only a name but released
+// in different years. The first game info of every group is the one to
+// keep: the one with an IGDB ID, then the one with the most downloads, then
+// the oldest in the order of infos.
+func FindDuplicateGameInfos(infos []*model.GameInfo) []*DuplicateGroup {
+	parent := make([]int, len(infos))
+	// the IGDB, Steam and GOG IDs and the release year of every group
+	ids := make([][4]int, len(infos))
+	uncertain := make([]bool, len(infos))
+	for i, info := range infos {
+		parent[i] = i
+		ids[i] = [4]int{info.IGDBID, info.SteamID, info.GOGID, gameInfoYear(info)}
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int, byName bool) {
+		a, b = find(a), find(b)
+		if a == b {
+			return
+		}
+		for k := range ids[a] {
+			if k == 3 && !byName {
+				continue
+			}
+			if ids[a][k] != 0 && ids[b][k] != 0 && ids[a][k] != ids[b][k] {
+				return
+			}
+		}
+		if byName && (ids[a][3] == 0 || ids[b][3] == 0) {
+			uncertain[a] = true
+		}
+		uncertain[a] = uncertain[a] || uncertain[b]
+		for k := range ids[a] {
+			if ids[a][k] == 0 {
+				ids[a][k] = ids[b][k]
+			}
+		}
+		parent[b] = a
+	}
+
+	// IDs are joined first, so a name only decides between groups that
+	// share no ID
+	seen := make(map[string]int)
+	for _, byName := range []bool{false, true} {
+		for i, info := range infos {
+			for _, key := range duplicateKeys(info) {
+				if strings.HasPrefix(key, "name:") != byName {
+					continue
+				}
+				if j, ok := seen[key]; ok {
+					union(j, i, byName)
+				} else {
+					seen[key] = i
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := range infos {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+	var res []*DuplicateGroup
+	for _, root := range roots {
+		members := groups[root]
+		if len(members) < 2 {
+			continue
+		}
+		sort.SliceStable(members, func(a, b int) bool {
+			x, y := infos[members[a]], infos[members[b]]
+			if (x.IGDBID != 0) != (y.IGDBID != 0) {
+				return x.IGDBID != 0
+			}
+			return len(x.GameIDs) > len(y.GameIDs)
+		})
+		group := &DuplicateGroup{
+			Infos:   make([]*model.GameInfo, 0, len(members)),
+			Certain: !uncertain[root],
+		}
+		for _, i := range members {
+			group.Infos = append(group.Infos, infos[i])
+		}
+		res = append(res, group)
+	}
+	return res
+}
+
+func duplicateKeys(info *model.GameInfo) []string {
+	var keys []string
+	if info.IGDBID != 0 {
+		keys = append(keys, fmt.Sprintf("igdb:%d", info.IGDBID))
+	}
+	if info.SteamID != 0 {
+		keys = append(keys, fmt.Sprintf("steam:%d", info.SteamID))
+	}
+	if info.GOGID != 0 {
+		keys = append(keys, fmt.Sprintf("gog:%d", info.GOGID))
+	}
+	for _, name := range append([]string{info.Name}, info.Aliases...) {
+		if name := strings.Join(utils.MatchTokens(name), " "); name != "" {
+			key := "name:" + name
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+func gameInfoYear(info *model.GameInfo) int {
+	if info.ReleaseDate == nil {
+		return 0
+	}
+	return info.ReleaseDate.Year()
+}
@@ -0,0 +1,69 @@
+package crawler
+
+import (
+	"GameDB/internal/model"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindDuplicateGameInfos(t *testing.T) {
+	doom1993 := time.Date(1993, time.December, 10, 0, 0, 0, 0, time.UTC)
+	doom2016 := time.Date(2016, time.May, 13, 0, 0, 0, 0, time.UTC)
+	hades := time.Date(2020, time.September, 17, 0, 0, 0, 0, time.UTC)
+	infos := []*model.GameInfo{
+		{Name: "The Witcher 3: Wild Hunt", SteamID: 292030},
+		{Name: "The Witcher 3 - Wild Hunt", IGDBID: 1942, GameIDs: make([]primitive.ObjectID, 1)},
+		{Name: "Witcher 3", Aliases: []string{"The Witcher III: Wild Hunt"}},
+		{Name: "Prey", IGDBID: 17444},
+		{Name: "Prey", IGDBID: 1231},
+		{Name: "Stardew Valley", GOGID: 1453375253},
+		{Name: "SDV", GOGID: 1453375253},
+		{Name: "Doom", ReleaseDate: &doom1993},
+		{Name: "DOOM", ReleaseDate: &doom2016},
+		{Name: "Hades", IGDBID: 113112, ReleaseDate: &hades},
+		{Name: "HADES", SteamID: 1145360, ReleaseDate: &hades},
+	}
+	groups := FindDuplicateGameInfos(infos)
+	if len(groups) != 3 {
+		t.Fatalf("found %d groups, want 3", len(groups))
+	}
+	if len(groups[0].Infos) != 3 || groups[0].Infos[0] != infos[1] || groups[0].Certain {
+		t.Errorf("witcher group = %+v, want 3 infos keeping the one with an IGDB ID, not certain", groups[0])
+	}
+	if len(groups[1].Infos) != 2 || groups[1].Infos[0] != infos[5] || !groups[1].Certain {
+		t.Errorf("stardew group = %+v", groups[1])
+	}
+	if len(groups[2].Infos) != 2 || groups[2].Infos[0] != infos[9] || !groups[2].Certain {
+		t.Errorf("hades group = %+v", groups[2])
+	}
+}
+
+func TestMergeGameInfo(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	keep := &model.GameInfo{Name: "Prey", IGDBID: 17444, GameIDs: []primitive.ObjectID{a}, Screenshots: []string{"1.jpg"}}
+	drop := &model.GameInfo{
+		Name:        "PREY",
+		Aliases:     []string{"Prey (2017)"},
+		IGDBID:      1,
+		SteamID:     480490,
+		Cover:       "cover.jpg",
+		GameIDs:     []primitive.ObjectID{a, b},
+		Screenshots: []string{"1.jpg", "2.jpg"},
+	}
+	conflicts := mergeGameInfo(keep, drop)
+	if !slices.Equal(conflicts, []string{"igdb"}) {
+		t.Errorf("conflicts = %v, want [igdb]", conflicts)
+	}
+	if keep.IGDBID != 17444 || keep.SteamID != 480490 || keep.Cover != "cover.jpg" {
+		t.Errorf("merged = %+v", keep)
+	}
+	if !slices.Equal(keep.GameIDs, []primitive.ObjectID{a, b}) || len(keep.Screenshots) != 2 {
+		t.Errorf("merged games %v and screenshots %v", keep.GameIDs, keep.Screenshots)
+	}
+	if !slices.Equal(keep.Aliases, []string{"Prey (2017)"}) {
+		t.Errorf("aliases = %v", keep.Aliases)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,34 +17,69 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/PuerkitoBio/goquery"
 	"go.uber.org/zap"
 )
 
+// cookies is the login session shared by concurrent crawls, guarded by
+// cookiesMu. loginMu makes concurrent crawls log in only once.
 var cookies map[string]string
+var cookiesMu sync.RWMutex
+var loginMu sync.Mutex
+
+type onlineFixSource struct{}
 
 func init() {
 	cookies = make(map[string]string)
+	RegisterSource(&onlineFixSource{})
 }
 
-func CrawlOnlineFix(page int) ([]*model.GameDownload, error) {
-	if !config.Config.OnlineFixAvaliable {
-		log.Logger.Error("Need Online Fix account")
-		return nil, errors.New("Online Fix is not available")
+func (s *onlineFixSource) Name() string {
+	return "onlinefix"
+}
+
+func (s *onlineFixSource) Author() string {
+	return "OnlineFix"
+}
+
+func (s *onlineFixSource) BaseURL() string {
+	return constant.OnlineFixURL
+}
+
+func (s *onlineFixSource) Formatter() Formatter {
+	return OnlineFixFormatter
+}
+
+func (s *onlineFixSource) FeedURL() string {
+	return feedURL(s.Name(), constant.OnlineFixFeedURL)
+}
+
+func (s *onlineFixSource) CrawlItem(ctx context.Context, u string, updateFlag string) (*model.GameDownload, error) {
+	if err := onlineFixLogin(ctx); err != nil {
+		return nil, err
 	}
-	if len(cookies) == 0 {
-		err := LoginOnlineFix()
-		if err != nil {
-			log.Logger.Error("Failed to login", zap.Error(err))
-			return nil, err
-		}
+	return crawlOnlineFixItem(ctx, u, updateFlag)
+}
+
+func (s *onlineFixSource) CrawlPage(ctx context.Context, page int) ([]*model.GameDownload, error) {
+	return CrawlOnlineFix(ctx, page, sourceConfig(s.Name()).Concurrency)
+}
+
+func (s *onlineFixSource) TotalPageNum(ctx context.Context) (int, error) {
+	return GetOnlineFixTotalPageNum(ctx)
+}
+
+func CrawlOnlineFix(ctx context.Context, page int, concurrency int) ([]*model.GameDownload, error) {
+	if err := onlineFixLogin(ctx); err != nil {
+		return nil, err
 	}
 	requestURL := fmt.Sprintf("%s/page/%d/", constant.OnlineFixURL, page)
 	log.Logger.Info("Crawling item", zap.String("url", requestURL))
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url:     requestURL,
-		Cookies: cookies,
+		Cookies: onlineFixCookies(),
 		Headers: map[string]string{
 			"Referer": constant.OnlineFixURL,
 		},
@@ -68,171 +104,159 @@ func CrawlOnlineFix(page int) ([]*model.GameDownload, error) {
 		)
 	})
 
-	var res []*model.GameDownload
-	for i, u := range urls {
-		if db.IsOnlineFixCrawled(updateFlags[i]) {
+	run := RunFromContext(ctx)
+	run.Found(len(urls))
+	return crawlItems(ctx, concurrency, len(urls), func(i int) (*model.GameDownload, error) {
+		u := urls[i]
+		if db.IsGameCrawled(ctx, updateFlags[i], "onlinefix") {
 			log.Logger.Info("Skipping already crawled item", zap.String("url", u))
-			continue
-		}
-		log.Logger.Info("Crawling item", zap.String("URL", u))
-		resp, err = utils.Fetch(utils.FetchConfig{
-			Url:     u,
-			Cookies: cookies,
-			Headers: map[string]string{
-				"Referer": constant.OnlineFixURL,
-			},
-		})
-		if err != nil {
-			log.Logger.Error("Failed to fetch", zap.Error(err))
-			continue
-		}
-		titleRegex := regexp.MustCompile(`(?i)<h1.*?>(.*?)</h1>`)
-		titleRegexRes := titleRegex.FindAllStringSubmatch(string(resp.Data), -1)
-		if len(titleRegexRes) == 0 {
-			log.Logger.Error("Failed to find title", zap.Error(err))
-			continue
-		}
-		downloadRegex := regexp.MustCompile(`(?i)<a[^>]*\bhref="([^"]+)"[^>]*>(Скачать Torrent|Скачать торрент)</a>`)
-		downloadRegexRes := downloadRegex.FindAllStringSubmatch(string(resp.Data), -1)
-		if len(downloadRegexRes) == 0 {
-			log.Logger.Error("Failed to find download button", zap.Error(err))
-			continue
+			run.Skipped()
+			return nil, nil
 		}
-		item, err := db.GetGameDownloadByUrl(u)
-		if err != nil {
-			log.Logger.Error("Failed to get game", zap.Error(err))
-			continue
+		return crawlOnlineFixItem(ctx, u, updateFlags[i])
+	})
+}
+
+func crawlOnlineFixItem(ctx context.Context, u string, updateFlag string) (*model.GameDownload, error) {
+	run := RunFromContext(ctx)
+	item, err := db.GetGameDownloadByUrl(ctx, u)
+	if err != nil {
+		log.Logger.Error("Failed to get game", zap.Error(err))
+		run.Failed(u, err.Error())
+		return nil, nil
+	}
+	item.Url = u
+	item.UpdateFlag = updateFlag
+	if err := fetchOnlineFixItem(ctx, item); err != nil {
+		log.Logger.Error("Failed to crawl item", zap.String("URL", u), zap.Error(err))
+		run.Failed(u, err.Error())
+		return nil, nil
+	}
+	return saveItem(ctx, u, item)
+}
+
+// fetchOnlineFixItem fills a game download from its page and the torrent
+// behind its download button.
+func fetchOnlineFixItem(ctx context.Context, item *model.GameDownload) error {
+	u := item.Url
+	log.Logger.Info("Crawling item", zap.String("URL", u))
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url:     u,
+		Cookies: onlineFixCookies(),
+		Headers: map[string]string{
+			"Referer": constant.OnlineFixURL,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	titleRegex := regexp.MustCompile(`(?i)<h1.*?>(.*?)</h1>`)
+	titleRegexRes := titleRegex.FindAllStringSubmatch(string(resp.Data), -1)
+	if len(titleRegexRes) == 0 {
+		return errors.New("failed to find title")
+	}
+	downloadRegex := regexp.MustCompile(`(?i)<a[^>]*\bhref="([^"]+)"[^>]*>(Скачать Torrent|Скачать торрент)</a>`)
+	downloadRegexRes := downloadRegex.FindAllStringSubmatch(string(resp.Data), -1)
+	if len(downloadRegexRes) == 0 {
+		return errors.New("failed to find download button")
+	}
+	item.RawName = titleRegexRes[0][1]
+	item.Name = SourceFormatter(&onlineFixSource{})(item.RawName)
+	item.Author = "OnlineFix"
+	item.Size = "0"
+	resp, err = utils.Fetch(ctx, utils.FetchConfig{
+		Url:     downloadRegexRes[0][1],
+		Cookies: onlineFixCookies(),
+		Headers: map[string]string{
+			"Referer": u,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if strings.Contains(downloadRegexRes[0][1], "uploads.online-fix.me") {
+		magnetRegex := regexp.MustCompile(`(?i)"(.*?).torrent"`)
+		magnetRegexRes := magnetRegex.FindAllStringSubmatch(string(resp.Data), -1)
+		if len(magnetRegexRes) == 0 {
+			return errors.New("failed to find magnet")
 		}
-		item.UpdateFlag = updateFlags[i]
-		item.RawName = titleRegexRes[0][1]
-		item.Name = OnlineFixFormatter(item.RawName)
-		item.Url = u
-		item.Author = "OnlineFix"
-		item.Size = "0"
-		resp, err = utils.Fetch(utils.FetchConfig{
-			Url:     downloadRegexRes[0][1],
-			Cookies: cookies,
+		log.Logger.Info("Found magnet", zap.String("magnet", downloadRegexRes[0][1]+strings.Trim(magnetRegexRes[0][0], "\"")))
+		resp, err = utils.Fetch(ctx, utils.FetchConfig{
+			Url:     downloadRegexRes[0][1] + strings.Trim(magnetRegexRes[0][0], "\""),
+			Cookies: onlineFixCookies(),
 			Headers: map[string]string{
 				"Referer": u,
 			},
 		})
 		if err != nil {
-			log.Logger.Error("Failed to fetch", zap.Error(err))
-			continue
+			return err
 		}
-		if strings.Contains(downloadRegexRes[0][1], "uploads.online-fix.me") {
-			magnetRegex := regexp.MustCompile(`(?i)"(.*?).torrent"`)
-			magnetRegexRes := magnetRegex.FindAllStringSubmatch(string(resp.Data), -1)
-			if len(magnetRegexRes) == 0 {
-				log.Logger.Error("Failed to find magnet", zap.Error(err))
-				continue
-			}
-			log.Logger.Info("Found magnet", zap.String("magnet", downloadRegexRes[0][1]+strings.Trim(magnetRegexRes[0][0], "\"")))
-			resp, err = utils.Fetch(utils.FetchConfig{
-				Url:     downloadRegexRes[0][1] + strings.Trim(magnetRegexRes[0][0], "\""),
-				Cookies: cookies,
-				Headers: map[string]string{
-					"Referer": u,
-				},
-			})
-			if err != nil {
-				log.Logger.Error("Failed to fetch", zap.Error(err))
-				continue
-			}
-			item.Magnet, item.Size, err = utils.ConvertTorrentToMagnet(resp.Data)
-			if err != nil {
-				log.Logger.Error("Failed to convert torrent to magnet", zap.Error(err))
-				continue
-			}
-		} else if strings.Contains(downloadRegexRes[0][1], "online-fix.me/ext") {
-			if strings.Contains(string(resp.Data), "mega.nz") {
-				if !config.Config.MegaAvaliable {
-					log.Logger.Error("Mega is not avaliable")
-					continue
-				}
-				megaRegex := regexp.MustCompile(`(?i)location.href=\\'([^\\']*)\\'`)
-				megaRegexRes := megaRegex.FindAllStringSubmatch(string(resp.Data), -1)
-				if len(megaRegexRes) == 0 {
-					log.Logger.Error("Failed to find download link")
-					continue
-				}
-				log.Logger.Info("Downloading torrent", zap.String("URL", megaRegexRes[0][1]))
-				path, files, err := utils.MegaDownload(megaRegexRes[0][1], "torrent")
-				if err != nil {
-					log.Logger.Error("Failed to download torrent", zap.Error(err))
-					continue
-				}
-				torrent := ""
-				for _, file := range files {
-					if strings.HasSuffix(file, ".torrent") {
-						torrent = file
-						break
-					}
-				}
-				dataBytes, err := os.ReadFile(torrent)
-				if err != nil {
-					log.Logger.Error("Failed to read torrent", zap.Error(err))
-					continue
-				}
-				item.Magnet, item.Size, err = utils.ConvertTorrentToMagnet(dataBytes)
-				if err != nil {
-					log.Logger.Error("Failed to convert torrent to magnet", zap.Error(err))
-					continue
-				}
-				err = os.RemoveAll(path)
-				if err != nil {
-					log.Logger.Error("Failed to remove torrent", zap.Error(err))
-				}
-			} else {
-				log.Logger.Error("Failed to find download link")
-				continue
-			}
-		} else {
-			log.Logger.Error("Failed to find download link")
-			continue
-		}
-		res = append(res, item)
-		err = db.SaveGameDownload(item)
+		item.Magnet, item.Size, err = utils.ConvertTorrentToMagnet(resp.Data)
 		if err != nil {
-			log.Logger.Error("Failed to save game", zap.Error(err))
-			continue
+			return err
 		}
+		item.Torrent = resp.Data
+		return nil
 	}
-	return res, nil
-}
-
-func CrawlOnlineFixMulti(pages []int) ([]*model.GameDownload, error) {
-	var res []*model.GameDownload
-	for _, page := range pages {
-		items, err := CrawlOnlineFix(page)
-		if err != nil {
-			return nil, err
+	if !strings.Contains(downloadRegexRes[0][1], "online-fix.me/ext") || !strings.Contains(string(resp.Data), "mega.nz") {
+		return errors.New("failed to find download link")
+	}
+	if !config.Config.MegaAvaliable {
+		return errors.New("mega is not avaliable")
+	}
+	megaRegex := regexp.MustCompile(`(?i)location.href=\\'([^\\']*)\\'`)
+	megaRegexRes := megaRegex.FindAllStringSubmatch(string(resp.Data), -1)
+	if len(megaRegexRes) == 0 {
+		return errors.New("failed to find download link")
+	}
+	log.Logger.Info("Downloading torrent", zap.String("URL", megaRegexRes[0][1]))
+	path, files, err := utils.MegaDownload(megaRegexRes[0][1], "torrent")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(path); err != nil {
+			log.Logger.Error("Failed to remove torrent", zap.Error(err))
 		}
-		res = append(res, items...)
+	}()
+	torrent := ""
+	for _, file := range files {
+		if strings.HasSuffix(file, ".torrent") {
+			torrent = file
+			break
+		}
+	}
+	dataBytes, err := os.ReadFile(torrent)
+	if err != nil {
+		return err
+	}
+	item.Magnet, item.Size, err = utils.ConvertTorrentToMagnet(dataBytes)
+	if err != nil {
+		return err
 	}
-	log.Logger.Info("Crawled finished", zap.Int("num", len(res)))
-	return res, nil
+	item.Torrent = dataBytes
+	return nil
 }
 
-func CrawlOnlineFixAll() ([]*model.GameDownload, error) {
-	var res []*model.GameDownload
-	totalPageNum, err := GetOnlineFixTotalPageNum()
-	if err != nil {
-		return nil, err
+func onlineFixLogin(ctx context.Context) error {
+	if !config.Config.OnlineFixAvaliable {
+		log.Logger.Error("Need Online Fix account")
+		return errors.New("Online Fix is not available")
 	}
-	for i := 1; i <= totalPageNum; i++ {
-		items, err := CrawlOnlineFix(i)
+	loginMu.Lock()
+	defer loginMu.Unlock()
+	if len(onlineFixCookies()) == 0 {
+		err := LoginOnlineFix(ctx)
 		if err != nil {
-			return nil, err
+			log.Logger.Error("Failed to login", zap.Error(err))
+			return err
 		}
-		res = append(res, items...)
 	}
-	log.Logger.Info("Crawled finished", zap.Int("num", len(res)))
-	return res, nil
+	return nil
 }
 
-func GetOnlineFixTotalPageNum() (int, error) {
-	resp, err := utils.Fetch(utils.FetchConfig{
+func GetOnlineFixTotalPageNum(ctx context.Context) (int, error) {
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.OnlineFixURL,
 		Headers: map[string]string{
 			"Referer": constant.OnlineFixURL,
@@ -261,8 +285,8 @@ type csrf struct {
 	Value string `json:"value"`
 }
 
-func LoginOnlineFix() error {
-	resp, err := utils.Fetch(utils.FetchConfig{
+func LoginOnlineFix(ctx context.Context) error {
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.OnlineFixCSRFURL,
 		Headers: map[string]string{
 			"X-Requested-With": "XMLHttpRequest",
@@ -279,18 +303,19 @@ func LoginOnlineFix() error {
 		return err
 	}
 
+	session := map[string]string{}
 	for _, cookie := range resp.Cookie {
-		cookies[cookie.Name] = cookie.Value
+		session[cookie.Name] = cookie.Value
 	}
 	params := url.Values{}
 	params.Add("login_name", config.Config.OnlineFix.User)
 	params.Add("login_password", config.Config.OnlineFix.Password)
 	params.Add(csrf.Field, csrf.Value)
 	params.Add("login", "submit")
-	resp, err = utils.Fetch(utils.FetchConfig{
+	resp, err = utils.Fetch(ctx, utils.FetchConfig{
 		Url:     constant.OnlineFixURL,
 		Method:  "POST",
-		Cookies: cookies,
+		Cookies: session,
 		Headers: map[string]string{
 			"Origin":       constant.OnlineFixURL,
 			"Content-Type": "application/x-www-form-urlencoded",
@@ -303,11 +328,25 @@ func LoginOnlineFix() error {
 		return err
 	}
 	for _, cookie := range resp.Cookie {
-		cookies[cookie.Name] = cookie.Value
+		session[cookie.Name] = cookie.Value
 	}
+	cookiesMu.Lock()
+	cookies = session
+	cookiesMu.Unlock()
 	return nil
 }
 
+// onlineFixCookies returns a copy of the login session.
+func onlineFixCookies() map[string]string {
+	cookiesMu.RLock()
+	defer cookiesMu.RUnlock()
+	res := make(map[string]string, len(cookies))
+	for k, v := range cookies {
+		res[k] = v
+	}
+	return res
+}
+
 func OnlineFixFormatter(name string) string {
 	name = strings.Replace(name, "по сети", "", -1)
 	reg1 := regexp.MustCompile(`(?i)\(.*?\)`)
@@ -0,0 +1,178 @@
+package crawler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/model"
+	"GameDB/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrIgnoredByOverride  = errors.New("ignored by match override")
+	ErrOverriddenBySource = errors.New("matched with another source by match override")
+)
+
+var matchOverrideIDTypes = []string{"igdb", "steam", "gog", model.MatchOverrideIgnore}
+
+// ValidateMatchOverride checks that an override has either a URL or a valid
+// pattern, and a platform ID unless it is ignored.
+func ValidateMatchOverride(override *model.MatchOverride) error {
+	if (override.URL == "") == (override.Pattern == "") {
+		return errors.New("match override needs either a url or a pattern")
+	}
+	if override.Pattern != "" {
+		if _, err := regexp.Compile(override.Pattern); err != nil {
+			return err
+		}
+	}
+	if !slices.Contains(matchOverrideIDTypes, override.IDType) {
+		return fmt.Errorf("invalid id type %q", override.IDType)
+	}
+	if override.IDType == model.MatchOverrideIgnore {
+		override.PlatformID = 0
+	} else if override.PlatformID <= 0 {
+		return errors.New("match override needs a platform id")
+	}
+	return nil
+}
+
+// MatchOverrides are the match overrides with their patterns compiled. Load
+// them once per run with LoadMatchOverrides.
+type MatchOverrides struct {
+	byURL    map[string]*model.MatchOverride
+	patterns []*matchOverridePattern
+}
+
+type matchOverridePattern struct {
+	re       *regexp.Regexp
+	override *model.MatchOverride
+}
+
+// LoadMatchOverrides loads all match overrides, overrides with an invalid
+// pattern are left out.
+func LoadMatchOverrides(ctx context.Context) (*MatchOverrides, error) {
+	overrides, err := db.GetMatchOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &MatchOverrides{byURL: map[string]*model.MatchOverride{}}
+	for _, override := range overrides {
+		if override.URL != "" {
+			if _, exist := res.byURL[override.URL]; !exist {
+				res.byURL[override.URL] = override
+			}
+			continue
+		}
+		re, err := regexp.Compile(override.Pattern)
+		if err != nil {
+			continue
+		}
+		res.patterns = append(res.patterns, &matchOverridePattern{re: re, override: override})
+	}
+	return res, nil
+}
+
+// Find returns the override of a game download or nil. An override of its
+// URL is preferred to the oldest pattern matching its raw name.
+func (o *MatchOverrides) Find(game *model.GameDownload) *model.MatchOverride {
+	if o == nil {
+		return nil
+	}
+	if override, exist := o.byURL[game.Url]; exist && game.Url != "" {
+		return override
+	}
+	for _, p := range o.patterns {
+		if p.re.MatchString(game.RawName) {
+			return p.override
+		}
+	}
+	return nil
+}
+
+// GetGameDownloadsByOverrides returns the game downloads organize applies
+// one of overrides to. Patterns are matched with the Go regexp like organize
+// does, not by MongoDB.
+func GetGameDownloadsByOverrides(ctx context.Context, overrides []*model.MatchOverride) ([]*model.GameDownload, error) {
+	all, err := LoadMatchOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// overrides are told apart by their URL and pattern, which are unique
+	applied := make(map[[2]string]bool, len(overrides))
+	urls := []string{}
+	for _, override := range overrides {
+		applied[[2]string{override.URL, override.Pattern}] = true
+		urls = append(urls, override.URL)
+	}
+	// only URL overrides can be looked up by MongoDB
+	if slices.ContainsFunc(overrides, func(override *model.MatchOverride) bool { return override.Pattern != "" }) {
+		urls = nil
+	}
+	var ids []primitive.ObjectID
+	err = db.EachGameDownloadName(ctx, urls, func(item *model.GameDownload) {
+		if override := all.Find(item); override != nil && applied[[2]string{override.URL, override.Pattern}] {
+			ids = append(ids, item.ID)
+		}
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return db.GetGameDownloadsByIDs(ctx, ids)
+}
+
+// SaveManualMatchOverride records a decision taken by hand on a game download
+// as an override of its URL, so matching it again keeps the decision.
+func SaveManualMatchOverride(ctx context.Context, gameID primitive.ObjectID, idtype string, id int, note string) error {
+	game, err := db.GetGameDownloadByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Url == "" {
+		return nil
+	}
+	override := &model.MatchOverride{
+		URL:        game.Url,
+		IDType:     idtype,
+		PlatformID: id,
+		Note:       note,
+	}
+	if err := ValidateMatchOverride(override); err != nil {
+		return err
+	}
+	return db.SaveMatchOverride(ctx, override)
+}
+
+// matchGame returns the ID of idtype a game download is overridden with, or
+// searches for it with match if it has no override.
+func matchGame(ctx context.Context, game *model.GameDownload, overrides *MatchOverrides, idtype string, match func(ctx context.Context, name string, year int) (*model.MatchResult, error)) (int, error) {
+	if override := overrides.Find(game); override != nil {
+		switch override.IDType {
+		case model.MatchOverrideIgnore:
+			return 0, ErrIgnoredByOverride
+		case idtype:
+			game.Match = &model.MatchResult{
+				Source:     idtype,
+				Query:      override.URL + override.Pattern,
+				ID:         override.PlatformID,
+				Confidence: 1,
+				MatchedAt:  time.Now(),
+			}
+			return override.PlatformID, nil
+		default:
+			return 0, ErrOverriddenBySource
+		}
+	}
+	res, err := match(ctx, game.Name, utils.ReleaseYear(game.RawName))
+	recordMatch(game, res)
+	if err != nil {
+		return 0, err
+	}
+	return res.ID, nil
+}
@@ -0,0 +1,87 @@
+package crawler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/model"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrGameDownloadNotFound = errors.New("game download not found")
+	ErrCandidateNotFound    = errors.New("candidate not found")
+	ErrNotAGame             = errors.New("marked as not a game in review")
+)
+
+// ReviewAccept links a game download with the game of idtype and id instead
+// of the game infos it was linked with, and closes its review.
+func ReviewAccept(ctx context.Context, gameID primitive.ObjectID, idtype string, id int) error {
+	if _, err := reviewedGameDownload(ctx, gameID); err != nil {
+		return err
+	}
+	// the game info is resolved first, so a failed accept keeps the links
+	info, err := getOrGenerateGameInfo(ctx, idtype, id)
+	if err != nil {
+		return err
+	}
+	if err := db.UnlinkGameDownloads(ctx, []primitive.ObjectID{gameID}, primitive.NilObjectID); err != nil {
+		return err
+	}
+	if err := linkGameInfo(ctx, info, gameID); err != nil {
+		return err
+	}
+	if err := SaveManualMatchOverride(ctx, gameID, idtype, id, "accepted in review"); err != nil {
+		return err
+	}
+	return db.SaveGameDownloadReview(ctx, gameID, &model.Review{
+		Status: model.ReviewStatusAccepted,
+		IDType: idtype,
+		ID:     id,
+	})
+}
+
+// ReviewAcceptCandidate accepts a candidate of the last match of a game
+// download, the best candidate if candidateID is 0.
+func ReviewAcceptCandidate(ctx context.Context, gameID primitive.ObjectID, candidateID int) error {
+	game, err := reviewedGameDownload(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if game.Match == nil {
+		return ErrCandidateNotFound
+	}
+	for _, candidate := range game.Match.Candidates {
+		if candidateID == 0 || candidate.ID == candidateID {
+			return ReviewAccept(ctx, gameID, game.Match.Source, candidate.LinkID())
+		}
+	}
+	return ErrCandidateNotFound
+}
+
+// ReviewNotAGame unlinks a game download from its game infos and keeps
+// organize from matching it again.
+func ReviewNotAGame(ctx context.Context, gameID primitive.ObjectID) error {
+	if _, err := reviewedGameDownload(ctx, gameID); err != nil {
+		return err
+	}
+	if err := db.UnlinkGameDownloads(ctx, []primitive.ObjectID{gameID}, primitive.NilObjectID); err != nil {
+		return err
+	}
+	if err := SaveManualMatchOverride(ctx, gameID, model.MatchOverrideIgnore, 0, "not a game in review"); err != nil {
+		return err
+	}
+	return db.SaveGameDownloadReview(ctx, gameID, &model.Review{Status: model.ReviewStatusNotAGame})
+}
+
+func reviewedGameDownload(ctx context.Context, gameID primitive.ObjectID) (*model.GameDownload, error) {
+	game, err := db.GetGameDownloadByID(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.ID.IsZero() {
+		return nil, ErrGameDownloadNotFound
+	}
+	return game, nil
+}
@@ -0,0 +1,85 @@
+package crawler
+
+import (
+	"GameDB/internal/log"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	formatterRulesMu sync.RWMutex
+	formatterRules   = map[string]Formatter{}
+)
+
+// SourceFormatter returns the formatter compiled from the rules file of src,
+// or the built-in formatter of src if it has no rules file.
+func SourceFormatter(src Source) Formatter {
+	formatterRulesMu.RLock()
+	defer formatterRulesMu.RUnlock()
+	if formatter, ok := formatterRules[src.Name()]; ok {
+		return formatter
+	}
+	return src.Formatter()
+}
+
+// LoadFormatterRules loads the rules file <source>.yaml, .yml or .json of every
+// source in dir. The rules of all sources are replaced at once, if any file is
+// invalid the rules loaded before are kept. Returns the names of the sources
+// with rules.
+func LoadFormatterRules(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	rules := map[string]Formatter{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		ext := filepath.Ext(entry.Name())
+		var steps []FormatterRule
+		switch strings.ToLower(ext) {
+		case ".yaml", ".yml":
+			err = decodeFile(path, func(data []byte) error { return yaml.Unmarshal(data, &steps) })
+		case ".json":
+			err = decodeFile(path, func(data []byte) error { return json.Unmarshal(data, &steps) })
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		name := strings.ToLower(strings.TrimSuffix(entry.Name(), ext))
+		src, ok := GetSource(name)
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown source %s", path, name)
+		}
+		if _, exist := rules[name]; exist {
+			return nil, fmt.Errorf("%s: source %s has more than one rules file", path, name)
+		}
+		formatter, err := compileFormatter(steps, src.Formatter())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		rules[name] = formatter
+	}
+	formatterRulesMu.Lock()
+	formatterRules = rules
+	formatterRulesMu.Unlock()
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	log.Logger.Info("Loaded formatter rules", zap.String("dir", dir), zap.Strings("sources", names))
+	return names, nil
+}
@@ -0,0 +1,39 @@
+package crawler
+
+import (
+	"GameDB/internal/log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestLoadFormatterRules(t *testing.T) {
+	log.Logger = zap.NewNop()
+	src, _ := GetSource("fitgirl")
+	dir := t.TempDir()
+	rules := "- remove: '(?i)^The\\s+'\n- builtin: true\n"
+	if err := os.WriteFile(filepath.Join(dir, "fitgirl.yaml"), []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFormatterRules(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = LoadFormatterRules(t.TempDir()) })
+	raw := "The Witcher 3: Wild Hunt - Complete Edition (v4.04)"
+	if name := SourceFormatter(src)(raw); name != "Witcher 3: Wild Hunt" {
+		t.Errorf("SourceFormatter(fitgirl)(%q) = %q", raw, name)
+	}
+
+	// an invalid file keeps the rules loaded before
+	if err := os.WriteFile(filepath.Join(dir, "dodi.yaml"), []byte("- cut: '('\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFormatterRules(dir); err == nil {
+		t.Error("LoadFormatterRules accepted an invalid regex")
+	}
+	if name := SourceFormatter(src)(raw); name != "Witcher 3: Wild Hunt" {
+		t.Errorf("rules were replaced after a failed reload: %q", name)
+	}
+}
@@ -0,0 +1,120 @@
+package crawler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Run records the outcome of a crawl or organize run in the crawl_runs
+// collection. All methods are safe to call on a nil *Run, so crawlers can
+// report to RunFromContext(ctx) whether or not a run was started.
+type Run struct {
+	mu   sync.Mutex
+	data *model.CrawlRun
+}
+
+type runContextKey struct{}
+
+func StartRun(ctx context.Context, runType string, source string) (context.Context, *Run) {
+	r := &Run{
+		data: &model.CrawlRun{
+			Type:      runType,
+			Source:    source,
+			Status:    model.CrawlRunStatusRunning,
+			StartedAt: time.Now(),
+		},
+	}
+	if err := db.SaveCrawlRun(ctx, r.data); err != nil {
+		log.Logger.Warn("Failed to save crawl run", zap.Error(err))
+	}
+	return context.WithValue(ctx, runContextKey{}, r), r
+}
+
+func RunFromContext(ctx context.Context) *Run {
+	r, _ := ctx.Value(runContextKey{}).(*Run)
+	return r
+}
+
+func (r *Run) Finish(ctx context.Context, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.data.FinishedAt = time.Now()
+	switch {
+	case err == nil && len(r.data.FailedPages) > 0:
+		r.data.Status = model.CrawlRunStatusPartial
+	case err == nil:
+		r.data.Status = model.CrawlRunStatusSuccess
+	case errors.Is(err, context.Canceled):
+		r.data.Status = model.CrawlRunStatusCanceled
+		r.data.Error = err.Error()
+	default:
+		r.data.Status = model.CrawlRunStatusFailed
+		r.data.Error = err.Error()
+	}
+	data := *r.data
+	r.mu.Unlock()
+	// the run has to be saved even if ctx is what ended it
+	if err := db.SaveCrawlRun(context.WithoutCancel(ctx), &data); err != nil {
+		log.Logger.Warn("Failed to save crawl run", zap.Error(err))
+	}
+	log.Logger.Info(
+		"Run finished",
+		zap.String("type", data.Type),
+		zap.String("source", data.Source),
+		zap.String("status", data.Status),
+		zap.Int("found", data.Found),
+		zap.Int("skipped", data.Skipped),
+		zap.Int("saved", data.Saved),
+		zap.Int("matched", data.Matched),
+		zap.Int("failed", data.Failed),
+	)
+}
+
+func (r *Run) update(f func(data *model.CrawlRun)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f(r.data)
+}
+
+func (r *Run) Page(page int) {
+	r.update(func(data *model.CrawlRun) { data.Pages = append(data.Pages, page) })
+}
+
+func (r *Run) PagesFailed(pages []int) {
+	r.update(func(data *model.CrawlRun) { data.FailedPages = append(data.FailedPages, pages...) })
+}
+
+func (r *Run) Found(num int) {
+	r.update(func(data *model.CrawlRun) { data.Found += num })
+}
+
+func (r *Run) Skipped() {
+	r.update(func(data *model.CrawlRun) { data.Skipped++ })
+}
+
+func (r *Run) Saved() {
+	r.update(func(data *model.CrawlRun) { data.Saved++ })
+}
+
+func (r *Run) Matched() {
+	r.update(func(data *model.CrawlRun) { data.Matched++ })
+}
+
+func (r *Run) Failed(url string, reason string) {
+	r.update(func(data *model.CrawlRun) {
+		data.Failed++
+		data.Failures = append(data.Failures, model.CrawlFailure{Url: url, Reason: reason})
+	})
+}
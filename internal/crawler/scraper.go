@@ -0,0 +1,510 @@
+package crawler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"GameDB/internal/utils"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// ScraperDefinition describes a "list page -> detail page" site so it can be
+// crawled without writing Go code. Definitions are loaded from YAML or JSON
+// files by LoadScrapers.
+type ScraperDefinition struct {
+	Name      string          `json:"name" yaml:"name"`
+	Author    string          `json:"author" yaml:"author"`
+	BaseURL   string          `json:"base_url" yaml:"base_url"`
+	Feed      string          `json:"feed" yaml:"feed"`
+	List      ScraperList     `json:"list" yaml:"list"`
+	Detail    ScraperDetail   `json:"detail" yaml:"detail"`
+	Formatter []FormatterRule `json:"formatter" yaml:"formatter"`
+}
+
+type ScraperList struct {
+	// URL of the list, a {page} placeholder makes the source paged
+	URL string `json:"url" yaml:"url"`
+	// Item selects every item of the list
+	Item string `json:"item" yaml:"item"`
+	// Link is the detail page of an item, the href of the item by default
+	Link ScraperField `json:"link" yaml:"link"`
+	// UpdateFlag is appended to the link to detect updated items
+	UpdateFlag ScraperField `json:"update_flag" yaml:"update_flag"`
+	// TotalPages is read from the first page of a paged source
+	TotalPages ScraperField `json:"total_pages" yaml:"total_pages"`
+}
+
+type ScraperDetail struct {
+	Title   ScraperField `json:"title" yaml:"title"`
+	Size    ScraperField `json:"size" yaml:"size"`
+	Magnet  ScraperField `json:"magnet" yaml:"magnet"`
+	Torrent ScraperField `json:"torrent" yaml:"torrent"`
+}
+
+// ScraperField extracts a value from a page or an item. The first element
+// matched by Selector is used, or the page or item itself if Selector is
+// empty. The value is the Attr attribute of the element or its text. Regex is
+// applied to the value, or to the HTML if neither Selector nor Attr is set,
+// and its first group (or the whole match) is kept. Decode can be "base64".
+type ScraperField struct {
+	Selector string `json:"selector" yaml:"selector"`
+	Attr     string `json:"attr" yaml:"attr"`
+	Regex    string `json:"regex" yaml:"regex"`
+	Decode   string `json:"decode" yaml:"decode"`
+
+	regex *regexp.Regexp
+}
+
+// FormatterRule is one step of a formatter, steps are applied in order and
+// the result is trimmed. Cut drops everything from the first match of the
+// pattern, Remove deletes every match and Replace replaces every match with
+// With. Builtin applies the Go formatter of the source, rules files use it to
+// fix names before or after the default formatting.
+type FormatterRule struct {
+	Cut     string `json:"cut,omitempty" yaml:"cut,omitempty"`
+	Remove  string `json:"remove,omitempty" yaml:"remove,omitempty"`
+	Replace string `json:"replace,omitempty" yaml:"replace,omitempty"`
+	With    string `json:"with,omitempty" yaml:"with,omitempty"`
+	Builtin bool   `json:"builtin,omitempty" yaml:"builtin,omitempty"`
+}
+
+func (f *ScraperField) empty() bool {
+	return f.Selector == "" && f.Attr == "" && f.Regex == ""
+}
+
+func (f *ScraperField) compile() error {
+	if f.Regex != "" {
+		re, err := regexp.Compile(f.Regex)
+		if err != nil {
+			return err
+		}
+		f.regex = re
+	}
+	if f.Decode != "" && f.Decode != "base64" {
+		return fmt.Errorf("unsupported decode %q", f.Decode)
+	}
+	return nil
+}
+
+func (f *ScraperField) extract(root *goquery.Selection, raw string) (string, bool) {
+	sel := root
+	if f.Selector != "" {
+		sel = root.Find(f.Selector).First()
+		if sel.Length() == 0 {
+			return "", false
+		}
+	}
+	var value string
+	switch {
+	case f.Attr != "":
+		v, exist := sel.Attr(f.Attr)
+		if !exist {
+			return "", false
+		}
+		value = v
+	case f.Selector == "" && f.regex != nil:
+		value = raw
+	default:
+		value = sel.Text()
+	}
+	if f.regex != nil {
+		match := f.regex.FindStringSubmatch(value)
+		if match == nil {
+			return "", false
+		}
+		value = match[0]
+		if len(match) > 1 {
+			value = match[1]
+		}
+		if f.Selector == "" && f.Attr == "" {
+			value = html.UnescapeString(value)
+		}
+	}
+	if f.Decode == "base64" {
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", false
+		}
+		value = string(data)
+	}
+	value = strings.TrimSpace(value)
+	return value, value != ""
+}
+
+// CompileFormatter builds a Formatter from rules.
+func CompileFormatter(rules []FormatterRule) (Formatter, error) {
+	return compileFormatter(rules, nil)
+}
+
+func compileFormatter(rules []FormatterRule, builtin Formatter) (Formatter, error) {
+	type step struct {
+		re   *regexp.Regexp
+		rule FormatterRule
+	}
+	steps := make([]step, 0, len(rules))
+	for _, rule := range rules {
+		var pattern string
+		switch {
+		case rule.Builtin:
+			if builtin == nil {
+				return nil, errors.New("formatter rule builtin needs a source with a built-in formatter")
+			}
+			steps = append(steps, step{rule: rule})
+			continue
+		case rule.Cut != "":
+			pattern = rule.Cut
+		case rule.Remove != "":
+			pattern = rule.Remove
+		case rule.Replace != "":
+			pattern = rule.Replace
+		default:
+			return nil, errors.New("formatter rule needs cut, remove or replace")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step{re: re, rule: rule})
+	}
+	return func(name string) string {
+		for _, s := range steps {
+			switch {
+			case s.rule.Builtin:
+				name = builtin(name)
+			case s.rule.Cut != "":
+				if index := s.re.FindStringIndex(name); index != nil {
+					name = name[:index[0]]
+				}
+			case s.rule.Remove != "":
+				name = s.re.ReplaceAllString(name, "")
+			default:
+				name = s.re.ReplaceAllString(name, s.rule.With)
+			}
+		}
+		return strings.TrimSpace(name)
+	}, nil
+}
+
+func (d *ScraperDefinition) compile() (Formatter, error) {
+	d.Name = strings.ToLower(d.Name)
+	if d.Name == "" || d.Author == "" || d.List.URL == "" || d.List.Item == "" {
+		return nil, errors.New("name, author, list.url and list.item are required")
+	}
+	if d.Detail.Title.empty() {
+		return nil, errors.New("detail.title is required")
+	}
+	if d.Detail.Magnet.empty() && d.Detail.Torrent.empty() {
+		return nil, errors.New("detail.magnet or detail.torrent is required")
+	}
+	if d.List.Link.empty() {
+		d.List.Link.Attr = "href"
+	}
+	if d.BaseURL == "" {
+		u, err := url.Parse(d.List.URL)
+		if err != nil {
+			return nil, err
+		}
+		d.BaseURL = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	}
+	for _, f := range []*ScraperField{
+		&d.List.Link, &d.List.UpdateFlag, &d.List.TotalPages,
+		&d.Detail.Title, &d.Detail.Size, &d.Detail.Magnet, &d.Detail.Torrent,
+	} {
+		if err := f.compile(); err != nil {
+			return nil, err
+		}
+	}
+	return CompileFormatter(d.Formatter)
+}
+
+type scraper struct {
+	def       *ScraperDefinition
+	formatter Formatter
+}
+
+// listScraper is a scraper whose list URL has no {page} placeholder.
+type listScraper struct {
+	*scraper
+}
+
+type pagedScraper struct {
+	*scraper
+}
+
+func (s *scraper) Name() string {
+	return s.def.Name
+}
+
+func (s *scraper) Author() string {
+	return s.def.Author
+}
+
+func (s *scraper) BaseURL() string {
+	return s.def.BaseURL
+}
+
+func (s *scraper) Formatter() Formatter {
+	return s.formatter
+}
+
+func (s *scraper) FeedURL() string {
+	return feedURL(s.def.Name, s.def.Feed)
+}
+
+func (s *scraper) CrawlItem(ctx context.Context, pageURL string, updateFlag string) (*model.GameDownload, error) {
+	item, err := s.fetchItem(ctx, pageURL, updateFlag)
+	if item == nil || err != nil {
+		return nil, err
+	}
+	return saveItem(ctx, pageURL, item)
+}
+
+func (s *listScraper) CrawlList(ctx context.Context, num int) ([]*model.GameDownload, error) {
+	return s.crawl(ctx, s.def.List.URL, num)
+}
+
+func (s *pagedScraper) CrawlPage(ctx context.Context, page int) ([]*model.GameDownload, error) {
+	return s.crawl(ctx, s.pageURL(page), math.MaxInt)
+}
+
+func (s *pagedScraper) TotalPageNum(ctx context.Context) (int, error) {
+	if s.def.List.TotalPages.empty() {
+		return 1, nil
+	}
+	doc, data, err := fetchDocument(ctx, s.pageURL(1))
+	if err != nil {
+		return 0, err
+	}
+	pageStr, ok := s.def.List.TotalPages.extract(doc.Selection, string(data))
+	if !ok {
+		return 0, errors.New("total page num not found")
+	}
+	totalPageNum, err := strconv.Atoi(pageStr)
+	if err != nil {
+		return 0, err
+	}
+	return totalPageNum, nil
+}
+
+func (s *pagedScraper) pageURL(page int) string {
+	return strings.ReplaceAll(s.def.List.URL, "{page}", strconv.Itoa(page))
+}
+
+func fetchDocument(ctx context.Context, requestURL string) (*goquery.Document, []byte, error) {
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url: requestURL,
+	})
+	if err != nil {
+		log.Logger.Error("Failed to fetch", zap.Error(err))
+		return nil, nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(resp.Data))
+	if err != nil {
+		log.Logger.Error("Failed to parse HTML", zap.Error(err))
+		return nil, nil, err
+	}
+	return doc, resp.Data, nil
+}
+
+func (s *scraper) crawl(ctx context.Context, listURL string, num int) ([]*model.GameDownload, error) {
+	log.Logger.Info("Crawling item", zap.String("url", listURL))
+	doc, _, err := fetchDocument(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(listURL)
+	if err != nil {
+		return nil, err
+	}
+	urls := []string{}
+	updateFlags := []string{} //link+update_flag
+	doc.Find(s.def.List.Item).Each(func(i int, item *goquery.Selection) {
+		raw, _ := goquery.OuterHtml(item)
+		link, ok := s.def.List.Link.extract(item, raw)
+		if !ok {
+			return
+		}
+		ref, err := url.Parse(link)
+		if err != nil {
+			return
+		}
+		link = base.ResolveReference(ref).String()
+		flag, _ := s.def.List.UpdateFlag.extract(item, raw)
+		urls = append(urls, link)
+		updateFlags = append(updateFlags, link+flag)
+	})
+
+	run := RunFromContext(ctx)
+	run.Found(len(urls))
+	limit := newCrawlLimit(num)
+	return crawlItems(ctx, sourceConfig(s.def.Name).Concurrency, len(urls), func(i int) (*model.GameDownload, error) {
+		if limit.full() {
+			return nil, nil
+		}
+		if db.IsGameCrawled(ctx, updateFlags[i], s.def.Author) {
+			log.Logger.Info("Skipping already crawled item", zap.String("url", urls[i]))
+			run.Skipped()
+			return nil, nil
+		}
+		if !limit.reserve() {
+			return nil, nil
+		}
+		item, err := s.fetchItem(ctx, urls[i], updateFlags[i])
+		if item == nil || err != nil {
+			limit.release()
+			return nil, err
+		}
+		return saveItem(ctx, urls[i], item)
+	})
+}
+
+func (s *scraper) fetchItem(ctx context.Context, pageURL string, updateFlag string) (*model.GameDownload, error) {
+	run := RunFromContext(ctx)
+	log.Logger.Info("Crawling item", zap.String("url", pageURL))
+	doc, data, err := fetchDocument(ctx, pageURL)
+	if err != nil {
+		run.Failed(pageURL, err.Error())
+		return nil, nil
+	}
+	item, err := db.GetGameDownloadByUrl(ctx, pageURL)
+	if err != nil {
+		log.Logger.Error("Failed to get game item", zap.Error(err))
+		return nil, err
+	}
+	item.Url = pageURL
+	item.UpdateFlag = updateFlag
+	item.Author = s.def.Author
+	raw := string(data)
+	rawName, ok := s.def.Detail.Title.extract(doc.Selection, raw)
+	if !ok {
+		log.Logger.Warn("Failed to get title", zap.String("url", pageURL))
+		run.Failed(pageURL, "failed to get title")
+		return nil, nil
+	}
+	item.RawName = rawName
+	item.Name = SourceFormatter(s)(item.RawName)
+	item.Size, _ = s.def.Detail.Size.extract(doc.Selection, raw)
+	if magnet, ok := s.def.Detail.Magnet.extract(doc.Selection, raw); ok {
+		item.Magnet = magnet
+	} else if torrentURL, ok := s.def.Detail.Torrent.extract(doc.Selection, raw); ok {
+		torrent, err := s.fetchTorrent(ctx, pageURL, torrentURL)
+		if err != nil {
+			log.Logger.Warn("Failed to fetch torrent", zap.String("url", pageURL), zap.Error(err))
+			run.Failed(pageURL, err.Error())
+			return nil, nil
+		}
+		magnet, size, err := utils.ConvertTorrentToMagnet(torrent)
+		if err != nil {
+			log.Logger.Warn("Failed to convert torrent to magnet", zap.String("url", pageURL), zap.Error(err))
+			run.Failed(pageURL, err.Error())
+			return nil, nil
+		}
+		item.Magnet = magnet
+		item.Torrent = torrent
+		if item.Size == "" {
+			item.Size = size
+		}
+	} else {
+		log.Logger.Warn("Failed to get magnet", zap.String("url", pageURL))
+		run.Failed(pageURL, "failed to get magnet")
+		return nil, nil
+	}
+	return item, nil
+}
+
+func (s *scraper) fetchTorrent(ctx context.Context, pageURL string, torrentURL string) ([]byte, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := url.Parse(torrentURL)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url: base.ResolveReference(ref).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// NewScraper builds a source from a definition, it is paged if the list URL
+// contains a {page} placeholder.
+func NewScraper(def *ScraperDefinition) (Source, error) {
+	formatter, err := def.compile()
+	if err != nil {
+		return nil, err
+	}
+	s := &scraper{def: def, formatter: formatter}
+	if strings.Contains(def.List.URL, "{page}") {
+		return &pagedScraper{s}, nil
+	}
+	return &listScraper{s}, nil
+}
+
+// LoadScrapers registers a source for every .yaml, .yml and .json scraper
+// definition in dir. A missing dir is not an error.
+func LoadScrapers(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		var def ScraperDefinition
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml":
+			err = decodeFile(path, func(data []byte) error { return yaml.Unmarshal(data, &def) })
+		case ".json":
+			err = decodeFile(path, func(data []byte) error { return json.Unmarshal(data, &def) })
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		src, err := NewScraper(&def)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if _, exist := GetSource(src.Name()); exist {
+			return fmt.Errorf("%s: source %s already exists", path, src.Name())
+		}
+		RegisterSource(src)
+		log.Logger.Info("Loaded scraper", zap.String("source", src.Name()), zap.String("file", path))
+	}
+	return nil
+}
+
+func decodeFile(path string, decode func([]byte) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return decode(data)
+}
@@ -0,0 +1,82 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const scraperPage = `<html><body>
+<h1 class="title"> Hollow Knight &amp; Friends </h1>
+<p class="size">Size: 1.1 GB</p>
+<a class="magnet" href="magnet:?xt=urn:btih:abc">Magnet</a>
+<a class="encoded" href="https://example.com/go?u=bWFnbmV0Oj94dD11cm46YnRpaDphYmM=">Download</a>
+<script>var torrent = "https://example.com/t/1.torrent";</script>
+</body></html>`
+
+func TestScraperFieldExtract(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(scraperPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name  string
+		field ScraperField
+		value string
+		ok    bool
+	}{
+		{"text", ScraperField{Selector: "h1.title"}, "Hollow Knight & Friends", true},
+		{"attr", ScraperField{Selector: "a.magnet", Attr: "href"}, "magnet:?xt=urn:btih:abc", true},
+		{"text regex group", ScraperField{Selector: ".size", Regex: `Size:\s*(.+)`}, "1.1 GB", true},
+		{"attr regex whole match", ScraperField{Selector: "a.magnet", Attr: "href", Regex: `btih:\w+`}, "btih:abc", true},
+		{"raw html regex", ScraperField{Regex: `var torrent = "(.*?)"`}, "https://example.com/t/1.torrent", true},
+		{"base64", ScraperField{Selector: "a.encoded", Attr: "href", Regex: `u=(.*)`, Decode: "base64"}, "magnet:?xt=urn:btih:abc", true},
+		{"missing selector", ScraperField{Selector: ".missing"}, "", false},
+		{"missing attr", ScraperField{Selector: "h1.title", Attr: "href"}, "", false},
+		{"regex miss", ScraperField{Selector: ".size", Regex: `Length: (.+)`}, "", false},
+		{"bad base64", ScraperField{Selector: "h1.title", Decode: "base64"}, "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.field.compile(); err != nil {
+				t.Fatal(err)
+			}
+			value, ok := c.field.extract(doc.Selection, scraperPage)
+			if value != c.value || ok != c.ok {
+				t.Errorf("extract = %q, %v, want %q, %v", value, ok, c.value, c.ok)
+			}
+		})
+	}
+}
+
+func TestCompileFormatter(t *testing.T) {
+	cases := []struct {
+		name  string
+		rules []FormatterRule
+		raw   string
+		want  string
+	}{
+		{"cut", []FormatterRule{{Cut: `\s+v\d`}}, "Hollow Knight v1.5.78", "Hollow Knight"},
+		{"cut without match", []FormatterRule{{Cut: `\[`}}, "Hollow Knight", "Hollow Knight"},
+		{"remove", []FormatterRule{{Remove: `(?i)\s*-\s*repack`}}, "Hollow Knight - RePack - Repack", "Hollow Knight"},
+		{"replace", []FormatterRule{{Replace: `_`, With: " "}}, "Hollow_Knight", "Hollow Knight"},
+		{"in order", []FormatterRule{{Replace: `\.`, With: " "}, {Cut: `\sv\d`}}, "Hollow.Knight.v1.5", "Hollow Knight"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			formatter, err := CompileFormatter(c.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name := formatter(c.raw); name != c.want {
+				t.Errorf("formatter(%q) = %q, want %q", c.raw, name, c.want)
+			}
+		})
+	}
+	for _, rules := range [][]FormatterRule{{{}}, {{Cut: "("}}, {{Builtin: true}}} {
+		if _, err := CompileFormatter(rules); err == nil {
+			t.Errorf("CompileFormatter(%+v) accepted an invalid rule", rules)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package crawler
+
+import (
+	"GameDB/internal/config"
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"GameDB/internal/utils"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Formatter func(string) string
+
+// Source is a repack site that can be crawled. A source must implement
+// PagedSource or ListSource to declare how it is crawled.
+type Source interface {
+	// Name is the key used on the command line, e.g. "fitgirl"
+	Name() string
+	// Author is the value stored in GameDownload.Author
+	Author() string
+	// BaseURL is the site the source crawls, used for per-host rate limits
+	BaseURL() string
+	Formatter() Formatter
+}
+
+// PagedSource is a source whose items are listed on numbered pages.
+type PagedSource interface {
+	Source
+	CrawlPage(ctx context.Context, page int) ([]*model.GameDownload, error)
+	TotalPageNum(ctx context.Context) (int, error)
+}
+
+// ListSource is a source whose items are listed on a single page.
+type ListSource interface {
+	Source
+	CrawlList(ctx context.Context, num int) ([]*model.GameDownload, error)
+}
+
+const (
+	latestPageNum  = 3
+	pageRetries    = 2
+	pageRetryDelay = 30 * time.Second
+)
+
+var sources []Source
+
+func RegisterSource(src Source) {
+	for _, s := range sources {
+		if s.Name() == src.Name() {
+			panic("crawler: source registered twice: " + src.Name())
+		}
+	}
+	sources = append(sources, src)
+}
+
+func GetSource(name string) (Source, bool) {
+	name = strings.ToLower(name)
+	for _, s := range sources {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func Sources() []Source {
+	return sources
+}
+
+func SourceNames() []string {
+	names := make([]string, 0, len(sources))
+	for _, s := range sources {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+func sourceConfig(name string) config.Source {
+	cfg := config.Config.Sources[name]
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	return cfg
+}
+
+func applyRateLimit(src Source) {
+	cfg := sourceConfig(src.Name())
+	utils.SetRateLimit(src.BaseURL(), cfg.RateLimit, cfg.Burst)
+}
+
+// crawlLimit caps the number of items fetched by the concurrent workers of a
+// crawl. A worker reserves a slot before fetching and releases it if the item
+// is skipped or fails, so no item is fetched only to be thrown away.
+type crawlLimit struct {
+	max   int64
+	taken int64
+}
+
+func newCrawlLimit(max int) *crawlLimit {
+	return &crawlLimit{max: int64(max)}
+}
+
+func (l *crawlLimit) reserve() bool {
+	if atomic.AddInt64(&l.taken, 1) > l.max {
+		atomic.AddInt64(&l.taken, -1)
+		return false
+	}
+	return true
+}
+
+func (l *crawlLimit) full() bool {
+	return atomic.LoadInt64(&l.taken) >= l.max
+}
+
+func (l *crawlLimit) release() {
+	atomic.AddInt64(&l.taken, -1)
+}
+
+// crawlItems calls fn for indexes [0, n) with at most concurrency calls in
+// flight and returns the non-nil results in index order. The first error or
+// the cancellation of ctx stops scheduling and is returned once running calls
+// finish.
+func crawlItems(ctx context.Context, concurrency int, n int, fn func(i int) (*model.GameDownload, error)) ([]*model.GameDownload, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]*model.GameDownload, n)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		mu.Lock()
+		if firstErr == nil && ctx.Err() != nil {
+			firstErr = ctx.Err()
+		}
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			item, err := fn(i)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			results[i] = item
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	res := make([]*model.GameDownload, 0, n)
+	for _, item := range results {
+		if item != nil {
+			res = append(res, item)
+		}
+	}
+	return res, nil
+}
+
+// crawlPages crawls pages in order. A page that fails does not stop the
+// crawl, it is retried after the others up to pageRetries times and returned
+// in failed if it never succeeds. onPage is called with the index of every
+// page of the first pass and the pages failed so far.
+func crawlPages(ctx context.Context, src PagedSource, pages []int, onPage func(i int, failed []int)) (res []*model.GameDownload, failed []int, err error) {
+	run := RunFromContext(ctx)
+	crawl := func(page int) bool {
+		items, err := src.CrawlPage(ctx, page)
+		if err != nil {
+			log.Logger.Warn("Failed to crawl page", zap.String("source", src.Name()), zap.Int("page", page), zap.Error(err))
+			return false
+		}
+		run.Page(page)
+		res = append(res, items...)
+		return true
+	}
+	for i, page := range pages {
+		if ctx.Err() != nil {
+			return res, failed, ctx.Err()
+		}
+		if !crawl(page) {
+			failed = append(failed, page)
+		}
+		if onPage != nil {
+			onPage(i, failed)
+		}
+	}
+	for retry := 1; retry <= pageRetries && len(failed) > 0; retry++ {
+		timer := time.NewTimer(time.Duration(retry) * pageRetryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res, failed, ctx.Err()
+		case <-timer.C:
+		}
+		log.Logger.Info("Retry failed pages", zap.String("source", src.Name()), zap.Ints("pages", failed))
+		var stillFailed []int
+		for i, page := range failed {
+			if ctx.Err() != nil {
+				return res, append(stillFailed, failed[i:]...), ctx.Err()
+			}
+			if !crawl(page) {
+				stillFailed = append(stillFailed, page)
+			}
+		}
+		failed = stillFailed
+	}
+	return res, failed, nil
+}
+
+// saveItem saves an item crawled from url and reports it to the run. A torrent
+// that is already stored, by this item or another one, and a failed save skip
+// the item instead of stopping the crawl.
+func saveItem(ctx context.Context, url string, item *model.GameDownload) (*model.GameDownload, error) {
+	run := RunFromContext(ctx)
+	db.SetMagnetFields(item)
+	if unchanged, err := db.MarkGameDownloadCrawled(ctx, item); err != nil {
+		log.Logger.Warn("Failed to check crawled torrent", zap.String("url", url), zap.Error(err))
+	} else if unchanged {
+		log.Logger.Info("Skipping unchanged torrent", zap.String("url", url), zap.String("info_hash", item.InfoHash))
+		run.Skipped()
+		return nil, nil
+	}
+	if err := db.SaveGameDownload(ctx, item); err != nil {
+		if errors.Is(err, db.ErrDuplicateInfoHash) {
+			log.Logger.Info("Skipping already crawled torrent", zap.String("url", url), zap.String("info_hash", item.InfoHash))
+			run.Skipped()
+			return nil, nil
+		}
+		log.Logger.Error("Failed to save game item", zap.Error(err))
+		run.Failed(url, err.Error())
+		return nil, nil
+	}
+	run.Saved()
+	return item, nil
+}
+
+func CrawlMulti(ctx context.Context, src PagedSource, pages []int) ([]*model.GameDownload, error) {
+	applyRateLimit(src)
+	totalPageNum, err := src.TotalPageNum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var crawlable []int
+	for _, page := range pages {
+		if page > totalPageNum {
+			log.Logger.Warn("Current page exceed total page", zap.String("source", src.Name()), zap.Int("page", page))
+			continue
+		}
+		crawlable = append(crawlable, page)
+	}
+	res, failed, err := crawlPages(ctx, src, crawlable, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) > 0 {
+		log.Logger.Warn("Failed to crawl pages", zap.String("source", src.Name()), zap.Ints("pages", failed))
+		RunFromContext(ctx).PagesFailed(failed)
+	}
+	log.Logger.Info("Crawled finished", zap.String("source", src.Name()), zap.Int("num", len(res)))
+	return res, nil
+}
+
+// CrawlAll crawls every page of src and stores the last crawled page and the
+// failed pages in a checkpoint after each page. With resume it continues from
+// the checkpoint, retrying its failed pages first. The checkpoint is deleted
+// once all pages have been crawled.
+func CrawlAll(ctx context.Context, src PagedSource, resume bool) ([]*model.GameDownload, error) {
+	applyRateLimit(src)
+	totalPageNum, err := src.TotalPageNum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &model.CrawlCheckpoint{Source: src.Name()}
+	if resume {
+		saved, err := db.GetCrawlCheckpoint(ctx, src.Name())
+		if err != nil {
+			return nil, err
+		}
+		if saved != nil {
+			checkpoint = saved
+			log.Logger.Info(
+				"Resume crawl",
+				zap.String("source", src.Name()),
+				zap.Int("last_page", checkpoint.LastPage),
+				zap.Ints("failed_pages", checkpoint.FailedPages),
+			)
+		} else {
+			log.Logger.Info("No checkpoint found, crawl from first page", zap.String("source", src.Name()))
+		}
+	}
+	checkpoint.TotalPages = totalPageNum
+	retryNum := len(checkpoint.FailedPages)
+	pages := append([]int{}, checkpoint.FailedPages...)
+	for i := checkpoint.LastPage + 1; i <= totalPageNum; i++ {
+		pages = append(pages, i)
+	}
+	res, failed, err := crawlPages(ctx, src, pages, func(i int, failed []int) {
+		checkpoint.FailedPages = append([]int{}, failed...)
+		if i < retryNum {
+			// failed pages of the checkpoint that are not retried yet
+			checkpoint.FailedPages = append(checkpoint.FailedPages, pages[i+1:retryNum]...)
+		} else {
+			checkpoint.LastPage = pages[i]
+		}
+		if err := db.SaveCrawlCheckpoint(ctx, checkpoint); err != nil {
+			log.Logger.Warn("Failed to save crawl checkpoint", zap.String("source", src.Name()), zap.Error(err))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) > 0 {
+		log.Logger.Warn("Failed to crawl pages", zap.String("source", src.Name()), zap.Ints("pages", failed))
+		RunFromContext(ctx).PagesFailed(failed)
+		checkpoint.LastPage = totalPageNum
+		checkpoint.FailedPages = failed
+		if err := db.SaveCrawlCheckpoint(ctx, checkpoint); err != nil {
+			log.Logger.Warn("Failed to save crawl checkpoint", zap.String("source", src.Name()), zap.Error(err))
+		}
+	} else if err := db.DeleteCrawlCheckpoint(ctx, src.Name()); err != nil {
+		log.Logger.Warn("Failed to delete crawl checkpoint", zap.String("source", src.Name()), zap.Error(err))
+	}
+	log.Logger.Info("Crawled finished", zap.String("source", src.Name()), zap.Int("num", len(res)))
+	return res, nil
+}
+
+func CrawlList(ctx context.Context, src ListSource, num int) ([]*model.GameDownload, error) {
+	applyRateLimit(src)
+	return src.CrawlList(ctx, num)
+}
+
+// CrawlLatest crawls the newest items of a source, used by scheduled tasks.
+// Sources with a feed are crawled from it, falling back to their list pages
+// if the feed can not be read.
+func CrawlLatest(ctx context.Context, src Source) ([]*model.GameDownload, error) {
+	if s, ok := src.(FeedSource); ok && s.FeedURL() != "" {
+		res, err := CrawlFeed(ctx, s)
+		if err == nil || ctx.Err() != nil {
+			return res, err
+		}
+		log.Logger.Warn("Failed to crawl feed, crawl latest pages instead", zap.String("source", src.Name()), zap.Error(err))
+	}
+	switch s := src.(type) {
+	case PagedSource:
+		pages := make([]int, latestPageNum)
+		for i := range pages {
+			pages[i] = i + 1
+		}
+		return CrawlMulti(ctx, s, pages)
+	case ListSource:
+		return CrawlList(ctx, s, math.MaxInt)
+	default:
+		return nil, errors.New("source can not be crawled")
+	}
+}
@@ -0,0 +1,41 @@
+package crawler
+
+import (
+	"GameDB/internal/model"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCrawlLimit(t *testing.T) {
+	for _, concurrency := range []int{1, 4} {
+		items, fetched := crawlLimited(t, concurrency)
+		// fetched items are never thrown away, failed ones give their slot back
+		if len(items) != int(fetched) || fetched > 3 || (concurrency == 1 && fetched != 3) {
+			t.Errorf("concurrency %d: fetched %d items and kept %d, want at most 3 of both", concurrency, fetched, len(items))
+		}
+	}
+}
+
+func crawlLimited(t *testing.T, concurrency int) ([]*model.GameDownload, int64) {
+	limit := newCrawlLimit(3)
+	var fetched int64
+	items, err := crawlItems(context.Background(), concurrency, 20, func(i int) (*model.GameDownload, error) {
+		if limit.full() || !limit.reserve() {
+			return nil, nil
+		}
+		time.Sleep(time.Millisecond)
+		// every other item fails and gives its slot back
+		if i%2 == 0 {
+			limit.release()
+			return nil, nil
+		}
+		atomic.AddInt64(&fetched, 1)
+		return &model.GameDownload{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items, atomic.LoadInt64(&fetched)
+}
@@ -8,18 +8,25 @@ import (
 	"GameDB/internal/log"
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"go.uber.org/zap"
 )
 
-func GetSteamIDFromSearchPage(name string) (int, error) {
+var steamYearRegex = regexp.MustCompile(`\b(?:19|20)\d{2}\b`)
+
+func GetSteamIDFromSearchPage(ctx context.Context, name string, year int) (*model.MatchResult, error) {
 	log.Logger.Debug("Get Steam ID", zap.String("key", name))
 	baseURL, _ := url.Parse(constant.SteamSearchURL)
 	params := url.Values{}
@@ -27,48 +34,47 @@ func GetSteamIDFromSearchPage(name string) (int, error) {
 	baseURL.RawQuery = params.Encode()
 
 	log.Logger.Debug("Get Steam ID", zap.String("url", baseURL.String()))
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: baseURL.String(),
 	})
 	if err != nil {
 		log.Logger.Error("Failed to fetch", zap.String("url", baseURL.String()), zap.Error(err))
-		return 0, err
+		return nil, err
 	}
-	idRegex := regexp.MustCompile(`data-ds-appid="(.*?)"`)
-	nameRegex := regexp.MustCompile(`<span class="title">(.*?)</span>`)
-	idRegexRes := idRegex.FindAllStringSubmatch(string(resp.Data), -1)
-	nameRegexRes := nameRegex.FindAllStringSubmatch(string(resp.Data), -1)
-
-	if len(idRegexRes) == 0 {
-		return 0, errors.New("Steam ID not found")
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(resp.Data))
+	if err != nil {
+		log.Logger.Error("Failed to parse HTML", zap.Error(err))
+		return nil, err
 	}
-
-	maxSim := 0.0
-	maxSimID := 0
-	for i, id := range idRegexRes {
-		idStr := id[1]
-		nameStr := nameRegexRes[i][1]
+	var candidates []*matchCandidate
+	doc.Find("a[data-ds-appid]").Each(func(i int, s *goquery.Selection) {
+		idStr := s.AttrOr("data-ds-appid", "")
 		if index := strings.Index(idStr, ","); index != -1 {
 			idStr = idStr[:index]
 		}
-		if strings.EqualFold(strings.TrimSpace(nameStr), strings.TrimSpace(name)) {
-			log.Logger.Info("Steam ID found", zap.String("key", name), zap.String("id", idStr))
-			return strconv.Atoi(idStr)
-		} else {
-			sim := utils.Similarity(nameStr, name)
-			log.Logger.Debug("Similarity", zap.String("str1", name), zap.String("str2", nameStr), zap.Float64("similarity", sim))
-			if sim >= 0.8 && sim > maxSim {
-				maxSim = sim
-				maxSimID, _ = strconv.Atoi(idStr)
-			}
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return
+		}
+		c := &matchCandidate{
+			ID:   id,
+			Name: strings.TrimSpace(s.Find(".title").First().Text()),
 		}
+		if y := steamYearRegex.FindString(s.Find(".search_released").First().Text()); y != "" {
+			c.Year, _ = strconv.Atoi(y)
+		}
+		candidates = append(candidates, c)
+	})
+	if len(candidates) == 0 {
+		return nil, errors.New("Steam ID not found")
 	}
-	if maxSimID != 0 {
-		log.Logger.Info("Steam ID found", zap.String("key", name), zap.String("id", strconv.Itoa(maxSimID)))
-		return maxSimID, nil
+	res, err := matchCandidates("steam", name, year, candidates)
+	if err != nil {
+		log.Logger.Info("Steam ID not found", zap.String("key", name))
+		return res, err
 	}
-	log.Logger.Info("Steam ID not found", zap.String("key", name))
-	return 0, errors.New("Steam ID not found")
+	log.Logger.Info("Steam ID found", zap.String("key", name), zap.Int("id", res.ID), zap.Float64("confidence", res.Confidence))
+	return res, nil
 }
 
 func GetIDPrepared(key string) string {
@@ -79,31 +85,31 @@ func GetIDPrepared(key string) string {
 	return strings.TrimSpace(key)
 }
 
-func _GetSteamID(name string, prepare bool) (int, error) {
+func _GetSteamID(ctx context.Context, name string, year int, prepare bool) (*model.MatchResult, error) {
 	if prepare {
 		name = GetIDPrepared(name)
 	}
-	id, err := GetSteamIDFromSearchPage(name)
-	if err == nil {
-		return int(id), nil
-	}
-	return 0, errors.New("Steam ID not found")
+	return GetSteamIDFromSearchPage(ctx, name, year)
 }
 
-func GetSteamID(key string) (int, error) {
-	key = GetIDPrepared(key)
-	id, err := _GetSteamID(key, false)
-	if err == nil {
-		return id, nil
-	}
-	id, err = _GetSteamID(key, true)
-	if err == nil {
-		return id, nil
+// MatchSteam matches the name of a game with Steam, year is the release year
+// of the game or 0 if unknown.
+func MatchSteam(ctx context.Context, name string, year int) (*model.MatchResult, error) {
+	name = GetIDPrepared(name)
+	return matchPrepared(func(prepare bool) (*model.MatchResult, error) {
+		return _GetSteamID(ctx, name, year, prepare)
+	})
+}
+
+func GetSteamID(ctx context.Context, key string) (int, error) {
+	res, err := MatchSteam(ctx, key, 0)
+	if err != nil {
+		return 0, errors.New("Steam ID not found")
 	}
-	return 0, errors.New("Steam ID not found")
+	return res.ID, nil
 }
 
-func GetSteamIDCache(key string) (int, error) {
+func GetSteamIDCache(ctx context.Context, key string) (int, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("steam_id:%s", key)
 		val, exist := cache.Redis.Get(key)
@@ -114,7 +120,7 @@ func GetSteamIDCache(key string) (int, error) {
 			}
 			return id, nil
 		} else {
-			id, err := GetSteamID(key)
+			id, err := GetSteamID(ctx, key)
 			if err != nil {
 				return 0, err
 			}
@@ -125,18 +131,18 @@ func GetSteamIDCache(key string) (int, error) {
 			return id, nil
 		}
 	} else {
-		return GetSteamID(key)
+		return GetSteamID(ctx, key)
 	}
 }
 
-func GetSteamAppDetail(id int) (*model.SteamAppDetail, error) {
+func GetSteamAppDetail(ctx context.Context, id int) (*model.SteamAppDetail, error) {
 	baseURL, _ := url.Parse(constant.SteamAppDetailURL)
 	params := url.Values{}
 	params.Add("appids", strconv.Itoa(id))
 	// params.Add("l", "schinese")
 	baseURL.RawQuery = params.Encode()
 	log.Logger.Debug("Get Steam App Detail", zap.String("url", baseURL.String()))
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: baseURL.String(),
 		Headers: map[string]string{
 			"User-Agent": "",
@@ -160,7 +166,7 @@ func GetSteamAppDetail(id int) (*model.SteamAppDetail, error) {
 	return detail[strconv.Itoa(id)], nil
 }
 
-func GetSteamAppDetailCache(id int) (*model.SteamAppDetail, error) {
+func GetSteamAppDetailCache(ctx context.Context, id int) (*model.SteamAppDetail, error) {
 	if config.Config.RedisAvaliable {
 		key := fmt.Sprintf("steam_game:%d", id)
 		val, exist := cache.Redis.Get(key)
@@ -171,7 +177,7 @@ func GetSteamAppDetailCache(id int) (*model.SteamAppDetail, error) {
 			}
 			return &detail, nil
 		} else {
-			data, err := GetSteamAppDetail(id)
+			data, err := GetSteamAppDetail(ctx, id)
 			if err != nil {
 				return nil, err
 			}
@@ -187,14 +193,14 @@ func GetSteamAppDetailCache(id int) (*model.SteamAppDetail, error) {
 			return data, nil
 		}
 	} else {
-		return GetSteamAppDetail(id)
+		return GetSteamAppDetail(ctx, id)
 	}
 }
 
-func GetSteamAppDetailsCache(ids []int) (map[string]model.SteamAppDetail, error) {
+func GetSteamAppDetailsCache(ctx context.Context, ids []int) (map[string]model.SteamAppDetail, error) {
 	res := make(map[string]model.SteamAppDetail)
 	for _, id := range ids {
-		detail, err := GetSteamAppDetail(id)
+		detail, err := GetSteamAppDetail(ctx, id)
 		if err != nil {
 			return nil, err
 		}
@@ -203,9 +209,9 @@ func GetSteamAppDetailsCache(ids []int) (map[string]model.SteamAppDetail, error)
 	return res, nil
 }
 
-func GenerateSteamGameInfo(id int) (*model.GameInfo, error) {
+func GenerateSteamGameInfo(ctx context.Context, id int) (*model.GameInfo, error) {
 	item := &model.GameInfo{}
-	detail, err := GetSteamAppDetailCache(id)
+	detail, err := GetSteamAppDetailCache(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -220,21 +226,122 @@ func GenerateSteamGameInfo(id int) (*model.GameInfo, error) {
 		screenshots = append(screenshots, screenshot.PathFull)
 	}
 	item.Screenshots = screenshots
+	SetSteamAttributes(item, detail)
 	return item, nil
 }
 
-func ProcessGameWithSteam(game *model.GameDownload) (*model.GameInfo, error) {
-	id, err := GetSteamID(game.Name)
+var steamReleaseDateLayouts = []string{
+	"2 Jan, 2006",
+	"Jan 2, 2006",
+	"2 January, 2006",
+	"January 2, 2006",
+	"Jan 2006",
+	"January 2006",
+	"2006",
+}
+
+// SetSteamAttributes fills the Steam store metadata of a game info from its
+// app detail, and its release date if it has none yet.
+func SetSteamAttributes(item *model.GameInfo, detail *model.SteamAppDetail) {
+	data := detail.Data
+	steam := &model.SteamInfo{
+		ControllerSupport: data.ControllerSupport,
+		Metacritic:        data.Metacritic.Score,
+		Recommendations:   data.Recommendations.Total,
+		ComingSoon:        data.ReleaseDate.ComingSoon,
+	}
+	for _, genre := range data.Genres {
+		steam.Genres = append(steam.Genres, genre.Description)
+	}
+	for _, category := range data.Categories {
+		steam.Categories = append(steam.Categories, category.Description)
+	}
+	for platform, ok := range map[string]bool{
+		"windows": data.Platforms.Windows,
+		"mac":     data.Platforms.Mac,
+		"linux":   data.Platforms.Linux,
+	} {
+		if ok {
+			steam.Platforms = append(steam.Platforms, platform)
+		}
+	}
+	slices.Sort(steam.Platforms)
+	for _, movie := range data.Movies {
+		trailer := movie.Mp4.Max
+		if trailer == "" {
+			trailer = movie.Webm.Max
+		}
+		if trailer != "" {
+			steam.Trailers = append(steam.Trailers, trailer)
+		}
+	}
+	if requirements, ok := data.PcRequirements.(map[string]any); ok {
+		minimum, _ := requirements["minimum"].(string)
+		recommended, _ := requirements["recommended"].(string)
+		reqs := &model.SteamRequirements{
+			Minimum:     parseSteamRequirements(minimum),
+			Recommended: parseSteamRequirements(recommended),
+		}
+		if reqs.Minimum != nil || reqs.Recommended != nil {
+			steam.Requirements = reqs
+		}
+	}
+	steam.ReleaseDate = parseSteamReleaseDate(data.ReleaseDate.Date)
+	if item.ReleaseDate == nil && !steam.ComingSoon {
+		item.ReleaseDate = steam.ReleaseDate
+	}
+	item.Steam = steam
+}
+
+func parseSteamReleaseDate(date string) *time.Time {
+	date = strings.TrimSpace(date)
+	for _, layout := range steamReleaseDateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// parseSteamRequirements reads the labeled lines of the requirements HTML of
+// a Steam app, e.g. "<li><strong>OS:</strong> Windows 10</li>", nil if there
+// are none.
+func parseSteamRequirements(html string) map[string]string {
+	if html == "" {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	var requirements map[string]string
+	doc.Find("li").Each(func(i int, li *goquery.Selection) {
+		label := strings.TrimSpace(li.Find("strong").First().Text())
+		value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(li.Text()), label))
+		label = strings.TrimSuffix(label, ":")
+		if label == "" || value == "" {
+			return
+		}
+		if requirements == nil {
+			requirements = make(map[string]string)
+		}
+		requirements[label] = value
+	})
+	return requirements
+}
+
+func ProcessGameWithSteam(ctx context.Context, game *model.GameDownload, overrides *MatchOverrides) (*model.GameInfo, error) {
+	id, err := matchGame(ctx, game, overrides, "steam", MatchSteam)
 	if err != nil {
 		return nil, err
 	}
-	d, err := db.GetGameInfoByPlatformID("steam", id)
+	d, err := db.GetGameInfoByPlatformID(ctx, "steam", id)
 	if err == nil {
 		d.GameIDs = append(d.GameIDs, game.ID)
 		d.GameIDs = utils.Unique(d.GameIDs)
 		return d, nil
 	}
-	detail, err := GenerateGameInfo("steam", id)
+	detail, err := GenerateGameInfo(ctx, "steam", id)
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ import (
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -18,8 +19,8 @@ import (
 	"go.uber.org/zap"
 )
 
-func GetSteam250(url string) ([]model.Steam250Item, error) {
-	resp, err := utils.Fetch(utils.FetchConfig{
+func GetSteam250(ctx context.Context, url string) ([]model.Steam250Item, error) {
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: url,
 	})
 	if err != nil {
@@ -47,39 +48,39 @@ func GetSteam250(url string) ([]model.Steam250Item, error) {
 	return res[:10], nil
 }
 
-func GetSteam250Top250() ([]model.Steam250Item, error) {
-	return GetSteam250(constant.Steam250Top250URL)
+func GetSteam250Top250(ctx context.Context) ([]model.Steam250Item, error) {
+	return GetSteam250(ctx, constant.Steam250Top250URL)
 }
 
-func GetSteam250Top250Cache() ([]model.Steam250Item, error) {
-	return GetSteam250Cache("top250", GetSteam250Top250)
+func GetSteam250Top250Cache(ctx context.Context) ([]model.Steam250Item, error) {
+	return GetSteam250Cache(ctx, "top250", GetSteam250Top250)
 }
 
-func GetSteam250BestOfTheYear() ([]model.Steam250Item, error) {
-	return GetSteam250(fmt.Sprintf(constant.Steam250BestOfTheYearURL, time.Now().UTC().Year()))
+func GetSteam250BestOfTheYear(ctx context.Context) ([]model.Steam250Item, error) {
+	return GetSteam250(ctx, fmt.Sprintf(constant.Steam250BestOfTheYearURL, time.Now().UTC().Year()))
 }
 
-func GetSteam250BestOfTheYearCache() ([]model.Steam250Item, error) {
-	return GetSteam250Cache(fmt.Sprintf("bestoftheyear:%v", time.Now().UTC().Year()), GetSteam250BestOfTheYear)
+func GetSteam250BestOfTheYearCache(ctx context.Context) ([]model.Steam250Item, error) {
+	return GetSteam250Cache(ctx, fmt.Sprintf("bestoftheyear:%v", time.Now().UTC().Year()), GetSteam250BestOfTheYear)
 }
 
-func GetSteam250WeekTop50() ([]model.Steam250Item, error) {
-	return GetSteam250(constant.Steam250WeekTop50URL)
+func GetSteam250WeekTop50(ctx context.Context) ([]model.Steam250Item, error) {
+	return GetSteam250(ctx, constant.Steam250WeekTop50URL)
 }
 
-func GetSteam250WeekTop50Cache() ([]model.Steam250Item, error) {
-	return GetSteam250Cache("weektop50", GetSteam250WeekTop50)
+func GetSteam250WeekTop50Cache(ctx context.Context) ([]model.Steam250Item, error) {
+	return GetSteam250Cache(ctx, "weektop50", GetSteam250WeekTop50)
 }
 
-func GetSteam250MostPlayed() ([]model.Steam250Item, error) {
-	return GetSteam250(constant.Steam250MostPlayedURL)
+func GetSteam250MostPlayed(ctx context.Context) ([]model.Steam250Item, error) {
+	return GetSteam250(ctx, constant.Steam250MostPlayedURL)
 }
 
-func GetSteam250MostPlayedCache() ([]model.Steam250Item, error) {
-	return GetSteam250Cache("mostplayed", GetSteam250MostPlayed)
+func GetSteam250MostPlayedCache(ctx context.Context) ([]model.Steam250Item, error) {
+	return GetSteam250Cache(ctx, "mostplayed", GetSteam250MostPlayed)
 }
 
-func GetSteam250Cache(k string, f func() ([]model.Steam250Item, error)) ([]model.Steam250Item, error) {
+func GetSteam250Cache(ctx context.Context, k string, f func(context.Context) ([]model.Steam250Item, error)) ([]model.Steam250Item, error) {
 	if config.Config.RedisAvaliable {
 		key := k
 		val, exist := cache.Redis.Get(key)
@@ -91,7 +92,7 @@ func GetSteam250Cache(k string, f func() ([]model.Steam250Item, error)) ([]model
 			}
 			return res, nil
 		} else {
-			data, err := f()
+			data, err := f(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -108,6 +109,6 @@ func GetSteam250Cache(k string, f func() ([]model.Steam250Item, error)) ([]model
 			return data, nil
 		}
 	} else {
-		return f()
+		return f(ctx)
 	}
 }
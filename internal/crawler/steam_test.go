@@ -0,0 +1,76 @@
+package crawler
+
+import (
+	"GameDB/internal/model"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+const steamAppDetailJSON = `{
+	"success": true,
+	"data": {
+		"name": "The Witcher 3: Wild Hunt",
+		"controller_support": "full",
+		"pc_requirements": {
+			"minimum": "<strong>Minimum:</strong><br><ul class=\"bb_ul\"><li>Requires a 64-bit processor and operating system<br></li><li><strong>OS:</strong> 64-bit Windows 10<br></li><li><strong>Processor:</strong> Intel Core i5-2500K<br></li></ul>",
+			"recommended": "<strong>Recommended:</strong><br><ul class=\"bb_ul\"><li><strong>Memory:</strong> 12 GB RAM<br></li></ul>"
+		},
+		"platforms": {"windows": true, "mac": false, "linux": true},
+		"metacritic": {"score": 93},
+		"categories": [{"id": 2, "description": "Single-player"}, {"id": 28, "description": "Full controller support"}],
+		"genres": [{"id": "3", "description": "RPG"}],
+		"movies": [{"mp4": {"max": "movie_max.mp4"}}, {"webm": {"max": "movie_max.webm"}}, {}],
+		"recommendations": {"total": 700000},
+		"release_date": {"coming_soon": false, "date": "18 May, 2015"}
+	}
+}`
+
+func TestSetSteamAttributes(t *testing.T) {
+	var detail model.SteamAppDetail
+	if err := json.Unmarshal([]byte(steamAppDetailJSON), &detail); err != nil {
+		t.Fatal(err)
+	}
+	item := &model.GameInfo{}
+	SetSteamAttributes(item, &detail)
+	steam := item.Steam
+	if steam == nil {
+		t.Fatal("Steam = nil")
+	}
+	if !slices.Equal(steam.Genres, []string{"RPG"}) || len(steam.Categories) != 2 || steam.ControllerSupport != "full" {
+		t.Errorf("genres %v, categories %v, controller %q", steam.Genres, steam.Categories, steam.ControllerSupport)
+	}
+	if !slices.Equal(steam.Platforms, []string{"linux", "windows"}) || steam.Metacritic != 93 || steam.Recommendations != 700000 {
+		t.Errorf("platforms %v, metacritic %d, recommendations %d", steam.Platforms, steam.Metacritic, steam.Recommendations)
+	}
+	if !slices.Equal(steam.Trailers, []string{"movie_max.mp4", "movie_max.webm"}) {
+		t.Errorf("trailers = %v", steam.Trailers)
+	}
+	if steam.Requirements == nil || len(steam.Requirements.Minimum) != 2 ||
+		steam.Requirements.Minimum["OS"] != "64-bit Windows 10" ||
+		steam.Requirements.Recommended["Memory"] != "12 GB RAM" {
+		t.Errorf("requirements = %+v", steam.Requirements)
+	}
+	want := time.Date(2015, time.May, 18, 0, 0, 0, 0, time.UTC)
+	if item.ReleaseDate == nil || !item.ReleaseDate.Equal(want) {
+		t.Errorf("release date = %v, want %v", item.ReleaseDate, want)
+	}
+}
+
+func TestParseSteamReleaseDate(t *testing.T) {
+	for date, want := range map[string]string{
+		"18 May, 2015": "2015-05-18",
+		"May 18, 2015": "2015-05-18",
+		"Dec 2024":     "2024-12-01",
+		"2025":         "2025-01-01",
+	} {
+		got := parseSteamReleaseDate(date)
+		if got == nil || got.Format(time.DateOnly) != want {
+			t.Errorf("parseSteamReleaseDate(%q) = %v, want %s", date, got, want)
+		}
+	}
+	if got := parseSteamReleaseDate("Coming soon"); got != nil {
+		t.Errorf("parseSteamReleaseDate(\"Coming soon\") = %v, want nil", got)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"GameDB/internal/model"
 	"GameDB/internal/utils"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -16,10 +18,48 @@ import (
 	"go.uber.org/zap"
 )
 
-func CrawlXatab(page int) ([]*model.GameDownload, error) {
+type xatabSource struct{}
+
+func init() {
+	RegisterSource(&xatabSource{})
+}
+
+func (s *xatabSource) Name() string {
+	return "xatab"
+}
+
+func (s *xatabSource) Author() string {
+	return "Xatab"
+}
+
+func (s *xatabSource) BaseURL() string {
+	return constant.XatabBaseURL
+}
+
+func (s *xatabSource) Formatter() Formatter {
+	return XatabFormatter
+}
+
+func (s *xatabSource) FeedURL() string {
+	return feedURL(s.Name(), constant.XatabFeedURL)
+}
+
+func (s *xatabSource) CrawlItem(ctx context.Context, url string, updateFlag string) (*model.GameDownload, error) {
+	return crawlXatabItem(ctx, url, updateFlag)
+}
+
+func (s *xatabSource) CrawlPage(ctx context.Context, page int) ([]*model.GameDownload, error) {
+	return CrawlXatab(ctx, page, sourceConfig(s.Name()).Concurrency)
+}
+
+func (s *xatabSource) TotalPageNum(ctx context.Context) (int, error) {
+	return GetXatabTotalPageNum(ctx)
+}
+
+func CrawlXatab(ctx context.Context, page int, concurrency int) ([]*model.GameDownload, error) {
 	requestURL := fmt.Sprintf("%s/page/%v", constant.XatabBaseURL, page)
 	log.Logger.Info("Crawling item", zap.String("url", requestURL))
-	resp, err := utils.Fetch(utils.FetchConfig{
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: requestURL,
 	})
 	if err != nil {
@@ -41,101 +81,73 @@ func CrawlXatab(page int) ([]*model.GameDownload, error) {
 				s.Find(".entry__info-categories").Text(),
 		)
 	})
-	var res []*model.GameDownload
-	for i := 0; i < len(urls); i++ {
-		if db.IsXatabCrawled(updateFlags[i]) {
+	run := RunFromContext(ctx)
+	run.Found(len(urls))
+	return crawlItems(ctx, concurrency, len(urls), func(i int) (*model.GameDownload, error) {
+		if db.IsGameCrawled(ctx, updateFlags[i], "xatab") {
 			log.Logger.Info("Skipping already crawled item", zap.String("URL", urls[i]))
-			continue
-		}
-		log.Logger.Info("Crawling item", zap.String("URL", urls[i]))
-		resp, err := utils.Fetch(utils.FetchConfig{
-			Url: urls[i],
-		})
-		if err != nil {
-			log.Logger.Error("Failed to fetch", zap.Error(err))
-			return nil, err
-		}
-		doc, err = goquery.NewDocumentFromReader(bytes.NewReader(resp.Data))
-		if err != nil {
-			log.Logger.Error("Failed to parse HTML", zap.Error(err))
-			return nil, err
+			run.Skipped()
+			return nil, nil
 		}
-		item, err := db.GetGameDownloadByUrl(urls[i])
-		if err != nil {
-			log.Logger.Error("Failed to get game item", zap.Error(err))
-			return nil, err
-		}
-		item.Url = urls[i]
-		item.UpdateFlag = updateFlags[i]
-		item.RawName = doc.Find(".inner-entry__title").First().Text()
-		item.Name = XatabFormatter(item.RawName)
-		item.Author = "Xatab"
-		downloadURL := doc.Find("#download>a").First().AttrOr("href", "")
-		if downloadURL == "" {
-			log.Logger.Error("Failed to find download URL", zap.String("item", item.Name))
-			continue
-		}
-		resp, err = utils.Fetch(utils.FetchConfig{
-			Url: downloadURL,
-		})
-		if err != nil {
-			log.Logger.Error("Failed to fetch", zap.Error(err))
-			continue
-		}
-		magnet, size, err := utils.ConvertTorrentToMagnet(resp.Data)
-		if err != nil {
-			log.Logger.Error("Failed to convert torrent to magnet", zap.Error(err))
-			continue
-		}
-		item.Size = size
-		item.Magnet = magnet
-		res = append(res, item)
-		err = db.SaveGameDownload(item)
-		if err != nil {
-			log.Logger.Error("Failed to save game item", zap.Error(err))
-		}
-	}
-	return res, nil
+		return crawlXatabItem(ctx, urls[i], updateFlags[i])
+	})
 }
 
-func CrawlXatabMulti(pages []int) ([]*model.GameDownload, error) {
-	totalPageNum, err := GetXatabTotalPageNum()
+func crawlXatabItem(ctx context.Context, url string, updateFlag string) (*model.GameDownload, error) {
+	run := RunFromContext(ctx)
+	item, err := db.GetGameDownloadByUrl(ctx, url)
 	if err != nil {
+		log.Logger.Error("Failed to get game item", zap.Error(err))
 		return nil, err
 	}
-	var res []*model.GameDownload
-	for _, page := range pages {
-		if page > totalPageNum {
-			log.Logger.Warn("Current page exceed total page", zap.Int("page", page))
-			continue
-		}
-		items, err := CrawlXatab(page)
-		if err != nil {
-			return nil, err
-		}
-		res = append(res, items...)
+	item.Url = url
+	item.UpdateFlag = updateFlag
+	if err := fetchXatabItem(ctx, item); err != nil {
+		log.Logger.Error("Failed to crawl item", zap.String("url", url), zap.Error(err))
+		run.Failed(url, err.Error())
+		return nil, nil
 	}
-	return res, nil
+	return saveItem(ctx, url, item)
 }
 
-func CrawlXatabAll() ([]*model.GameDownload, error) {
-	totalPageNum, err := GetXatabTotalPageNum()
+// fetchXatabItem fills a game download from its page and torrent file.
+func fetchXatabItem(ctx context.Context, item *model.GameDownload) error {
+	log.Logger.Info("Crawling item", zap.String("URL", item.Url))
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
+		Url: item.Url,
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var res []*model.GameDownload
-	for i := 1; i <= totalPageNum; i++ {
-		items, err := CrawlXatab(i)
-		if err != nil {
-			return nil, err
-		}
-		res = append(res, items...)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(resp.Data))
+	if err != nil {
+		return err
+	}
+	item.RawName = doc.Find(".inner-entry__title").First().Text()
+	item.Name = SourceFormatter(&xatabSource{})(item.RawName)
+	item.Author = "Xatab"
+	downloadURL := doc.Find("#download>a").First().AttrOr("href", "")
+	if downloadURL == "" {
+		return errors.New("failed to find download URL")
+	}
+	resp, err = utils.Fetch(ctx, utils.FetchConfig{
+		Url: downloadURL,
+	})
+	if err != nil {
+		return err
+	}
+	magnet, size, err := utils.ConvertTorrentToMagnet(resp.Data)
+	if err != nil {
+		return err
 	}
-	return res, nil
+	item.Size = size
+	item.Magnet = magnet
+	item.Torrent = resp.Data
+	return nil
 }
 
-func GetXatabTotalPageNum() (int, error) {
-	resp, err := utils.Fetch(utils.FetchConfig{
+func GetXatabTotalPageNum(ctx context.Context) (int, error) {
+	resp, err := utils.Fetch(ctx, utils.FetchConfig{
 		Url: constant.XatabBaseURL,
 	})
 	if err != nil {
@@ -1,17 +0,0 @@
-package db
-
-import (
-	"GameDB/internal/model"
-)
-
-func GetFitgirlAllGameDownloads() ([]*model.GameDownload, error) {
-	return GetAllGameDownloadsWithAuthor("fitgirl")
-}
-
-func GetDODIAllGameDownloads() ([]*model.GameDownload, error) {
-	return GetAllGameDownloadsWithAuthor("dodi")
-}
-
-func GetKaOsKrewAllGameDownloads() ([]*model.GameDownload, error) {
-	return GetAllGameDownloadsWithAuthor("kaoskrew")
-}
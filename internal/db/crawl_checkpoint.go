@@ -0,0 +1,50 @@
+package db
+
+import (
+	"GameDB/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetCrawlCheckpoint returns nil without error if source has no checkpoint.
+func GetCrawlCheckpoint(ctx context.Context, source string) (*model.CrawlCheckpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var checkpoint model.CrawlCheckpoint
+	err := CrawlCheckpointCollection.FindOne(ctx, bson.M{"_id": source}).Decode(&checkpoint)
+	if err != nil {
+		if errors.Is(mongo.ErrNoDocuments, err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func SaveCrawlCheckpoint(ctx context.Context, checkpoint *model.CrawlCheckpoint) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	checkpoint.UpdatedAt = time.Now()
+	filter := bson.M{"_id": checkpoint.Source}
+	opts := options.Replace().SetUpsert(true)
+	_, err := CrawlCheckpointCollection.ReplaceOne(ctx, filter, checkpoint, opts)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func DeleteCrawlCheckpoint(ctx context.Context, source string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, err := CrawlCheckpointCollection.DeleteOne(ctx, bson.M{"_id": source})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"GameDB/internal/model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func SaveCrawlRun(ctx context.Context, run *model.CrawlRun) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if run.ID.IsZero() {
+		run.ID = primitive.NewObjectID()
+	}
+	filter := bson.M{"_id": run.ID}
+	update := bson.M{"$set": run}
+	opts := options.Update().SetUpsert(true)
+	_, err := CrawlRunCollection.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetCrawlRuns(ctx context.Context, runType string, source string, status string, limit int) ([]*model.CrawlRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	filter := bson.M{}
+	if runType != "" {
+		filter["type"] = runType
+	}
+	if source != "" {
+		filter["source"] = source
+	}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	cursor, err := CrawlRunCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var runs []*model.CrawlRun
+	if err = cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
@@ -17,6 +18,16 @@ var MongoDB *mongo.Client
 var GameDownloadCollection *mongo.Collection
 var LanguageCollection *mongo.Collection
 var GameInfoCollection *mongo.Collection
+var CrawlRunCollection *mongo.Collection
+var CrawlCheckpointCollection *mongo.Collection
+var GameDownloadHistoryCollection *mongo.Collection
+var MatchOverrideCollection *mongo.Collection
+var GenreCollection *mongo.Collection
+var ThemeCollection *mongo.Collection
+var GameModeCollection *mongo.Collection
+var PerspectiveCollection *mongo.Collection
+var FranchiseCollection *mongo.Collection
+var TorrentBucket *gridfs.Bucket
 
 func InitDB() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -44,6 +55,22 @@ func InitDB() {
 	GameDownloadCollection = MongoDB.Database(config.Config.Database.Database).Collection("game_downloads")
 	LanguageCollection = MongoDB.Database(config.Config.Database.Database).Collection("languages")
 	GameInfoCollection = MongoDB.Database(config.Config.Database.Database).Collection("game_infos")
+	CrawlRunCollection = MongoDB.Database(config.Config.Database.Database).Collection("crawl_runs")
+	CrawlCheckpointCollection = MongoDB.Database(config.Config.Database.Database).Collection("crawl_checkpoints")
+	GameDownloadHistoryCollection = MongoDB.Database(config.Config.Database.Database).Collection("game_download_history")
+	MatchOverrideCollection = MongoDB.Database(config.Config.Database.Database).Collection("match_overrides")
+	GenreCollection = MongoDB.Database(config.Config.Database.Database).Collection("genres")
+	ThemeCollection = MongoDB.Database(config.Config.Database.Database).Collection("themes")
+	GameModeCollection = MongoDB.Database(config.Config.Database.Database).Collection("game_modes")
+	PerspectiveCollection = MongoDB.Database(config.Config.Database.Database).Collection("player_perspectives")
+	FranchiseCollection = MongoDB.Database(config.Config.Database.Database).Collection("franchises")
+	TorrentBucket, err = gridfs.NewBucket(
+		MongoDB.Database(config.Config.Database.Database),
+		options.GridFSBucket().SetName("torrents"),
+	)
+	if err != nil {
+		log.Logger.Panic("Failed to create GridFS bucket", zap.Error(err))
+	}
 
 	gameDetailsGamesIndex := mongo.IndexModel{
 		Keys: bson.D{
@@ -54,12 +81,77 @@ func InitDB() {
 	searchGameDetailsIndex := mongo.IndexModel{
 		Keys: bson.D{{Key: "name", Value: "text"}, {Key: "aliases", Value: "text"}},
 	}
+	sizeIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "size_bytes", Value: 1}},
+	}
+	crawlRunsIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "source", Value: 1},
+			{Key: "started_at", Value: -1},
+		},
+	}
+	historyIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "game_download_id", Value: 1},
+			{Key: "replaced_at", Value: -1},
+		},
+	}
+	matchOverrideIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "url", Value: 1},
+			{Key: "pattern", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	browseIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "genres", Value: 1}}},
+		{Keys: bson.D{{Key: "themes", Value: 1}}},
+		{Keys: bson.D{{Key: "game_modes", Value: 1}}},
+		{Keys: bson.D{{Key: "player_perspectives", Value: 1}}},
+		{Keys: bson.D{{Key: "franchises", Value: 1}}},
+		{Keys: bson.D{{Key: "release_date", Value: 1}}},
+		{Keys: bson.D{{Key: "rating", Value: 1}}},
+	}
+	referenceIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
 	_, err = GameDownloadCollection.Indexes().CreateOne(context.TODO(), gameDetailsGamesIndex)
 	if err != nil {
 		log.Logger.Error("Failed to create index", zap.Error(err))
 	}
+	_, err = GameDownloadCollection.Indexes().CreateOne(context.TODO(), sizeIndex)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
 	_, err = GameInfoCollection.Indexes().CreateOne(context.TODO(), searchGameDetailsIndex)
 	if err != nil {
 		log.Logger.Error("Failed to create index", zap.Error(err))
 	}
+	_, err = CrawlRunCollection.Indexes().CreateOne(context.TODO(), crawlRunsIndex)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
+	_, err = GameDownloadHistoryCollection.Indexes().CreateOne(context.TODO(), historyIndex)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
+	_, err = MatchOverrideCollection.Indexes().CreateOne(context.TODO(), matchOverrideIndex)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
+	_, err = GameInfoCollection.Indexes().CreateMany(context.TODO(), browseIndexes)
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
+	for _, collection := range []*mongo.Collection{GenreCollection, ThemeCollection, GameModeCollection, PerspectiveCollection, FranchiseCollection} {
+		_, err = collection.Indexes().CreateOne(context.TODO(), referenceIndex)
+		if err != nil {
+			log.Logger.Error("Failed to create index", zap.Error(err))
+		}
+	}
+	err = CreateInfoHashIndex(context.TODO())
+	if err != nil {
+		log.Logger.Error("Failed to create index", zap.Error(err))
+	}
 }
@@ -5,10 +5,12 @@ import (
 	"GameDB/internal/config"
 	"GameDB/internal/log"
 	"GameDB/internal/model"
+	"GameDB/internal/utils"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"slices"
 	"strings"
@@ -26,9 +28,9 @@ var (
 	removeRepeatingSpacesRegex = regexp.MustCompile(`\s+`)
 )
 
-func GetAllGameDownloadsWithAuthor(regex string) ([]*model.GameDownload, error) {
+func GetAllGameDownloadsWithAuthor(ctx context.Context, regex string) ([]*model.GameDownload, error) {
 	var items []*model.GameDownload
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	filter := bson.D{{Key: "author", Value: primitive.Regex{Pattern: regex, Options: "i"}}}
 	cursor, err := GameDownloadCollection.Find(ctx, filter)
@@ -49,11 +51,11 @@ func GetAllGameDownloadsWithAuthor(regex string) ([]*model.GameDownload, error)
 	return items, err
 }
 
-func IsGameCrawled(flag string, author string) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func IsGameCrawled(ctx context.Context, flag string, author string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	filter := bson.D{
-		{Key: "author", Value: primitive.Regex{Pattern: author, Options: "i"}},
+		{Key: "author", Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(author) + "$", Options: "i"}},
 		{Key: "update_flag", Value: flag},
 	}
 	var game model.GameDownload
@@ -68,8 +70,8 @@ func IsGameCrawled(flag string, author string) bool {
 	return true
 }
 
-func IsGameCrawledByURL(url string) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func IsGameCrawledByURL(ctx context.Context, url string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	filter := bson.D{
 		{Key: "url", Value: url},
@@ -86,8 +88,94 @@ func IsGameCrawledByURL(url string) bool {
 	return true
 }
 
-func SaveGameDownload(item *model.GameDownload) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// ErrDuplicateInfoHash is returned when saving a game download whose torrent
+// is already stored by another game download.
+var ErrDuplicateInfoHash = errors.New("game download with the same info-hash exists")
+
+// SetMagnetFields fills the info-hash, display name and trackers of item from
+// its magnet. They are cleared if the magnet is empty or can't be parsed.
+func SetMagnetFields(item *model.GameDownload) {
+	item.InfoHash = ""
+	item.DisplayName = ""
+	item.Trackers = nil
+	if item.Magnet == "" {
+		return
+	}
+	info, err := utils.ParseMagnet(item.Magnet)
+	if err != nil {
+		log.Logger.Warn("Failed to parse magnet", zap.String("url", item.Url), zap.Error(err))
+		return
+	}
+	item.InfoHash = info.InfoHash
+	item.DisplayName = info.DisplayName
+	item.Trackers = info.Trackers
+}
+
+// gameDownloadUpdate sets the fields of item and unsets the magnet fields and
+// release it no longer has, $set skips them as they are omitted when empty.
+func gameDownloadUpdate(item *model.GameDownload) bson.M {
+	update := bson.M{"$set": item}
+	unset := bson.M{}
+	if item.InfoHash == "" {
+		unset["info_hash"] = ""
+	}
+	if item.DisplayName == "" {
+		unset["display_name"] = ""
+	}
+	if len(item.Trackers) == 0 {
+		unset["trackers"] = ""
+	}
+	if item.Release == nil {
+		unset["release"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// MarkGameDownloadCrawled saves the update flag and update time of item if it
+// is stored with the same info-hash and raw name, and reports whether it was.
+// Crawlers use it to skip pages that changed while their torrent did not.
+func MarkGameDownloadCrawled(ctx context.Context, item *model.GameDownload) (bool, error) {
+	if item.ID.IsZero() || item.InfoHash == "" {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	filter := bson.M{"_id": item.ID, "info_hash": item.InfoHash, "raw_name": item.RawName}
+	set := bson.M{"updated_at": time.Now()}
+	if item.UpdateFlag != "" {
+		set["update_flag"] = item.UpdateFlag
+	}
+	res, err := GameDownloadCollection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// SetSizeBytes parses the size of item, falling back to the total length of
+// its files if the size is unknown.
+func SetSizeBytes(item *model.GameDownload) {
+	item.Size = strings.Replace(item.Size, "gb", "GB", -1)
+	item.Size = strings.Replace(item.Size, "mb", "MB", -1)
+	if size, err := utils.ParseSize(item.Size); err == nil {
+		item.SizeBytes = size
+		return
+	}
+	var size int64
+	for _, file := range item.Files {
+		size += file.Length
+	}
+	if size > 0 {
+		item.SizeBytes = size
+		item.Size = utils.FormatSize(size)
+	}
+}
+
+func SaveGameDownload(ctx context.Context, item *model.GameDownload) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	if item.ID.IsZero() {
 		item.ID = primitive.NewObjectID()
@@ -96,20 +184,81 @@ func SaveGameDownload(item *model.GameDownload) error {
 		item.CreatedAt = time.Now()
 	}
 	item.UpdatedAt = time.Now()
-	item.Size = strings.Replace(item.Size, "gb", "GB", -1)
-	item.Size = strings.Replace(item.Size, "mb", "MB", -1)
+	SetMagnetFields(item)
+	if item.InfoHash != "" {
+		filter := bson.M{"info_hash": item.InfoHash, "_id": bson.M{"$ne": item.ID}}
+		err := GameDownloadCollection.FindOne(ctx, filter).Err()
+		if err == nil {
+			return ErrDuplicateInfoHash
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+	}
+	var old *model.GameDownload
+	if err := GameDownloadCollection.FindOne(ctx, bson.M{"_id": item.ID}).Decode(&old); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		old = nil
+	}
+	// keep the replaced release if the magnet or raw name changed
+	var revision *model.GameDownloadRevision
+	if old != nil && old.Magnet != "" && (old.Magnet != item.Magnet || old.RawName != item.RawName) {
+		revision = newGameDownloadRevision(old, time.Now())
+	}
+	oldTorrentFileID := item.TorrentFileID
+	if item.Torrent != nil {
+		files, err := utils.GetTorrentFiles(item.Torrent)
+		if err != nil {
+			log.Logger.Warn("Failed to list torrent files", zap.String("url", item.Url), zap.Error(err))
+		} else {
+			item.Files = files
+		}
+		name := item.InfoHash
+		if name == "" {
+			name = item.ID.Hex()
+		}
+		fileID, err := SaveTorrentFile(ctx, item.ID, name+".torrent", item.Torrent)
+		if err != nil {
+			return err
+		}
+		item.TorrentFileID = fileID
+	}
+	SetSizeBytes(item)
+	item.Release = utils.ParseRelease(item.RawName)
 	filter := bson.M{"_id": item.ID}
-	update := bson.M{"$set": item}
+	update := gameDownloadUpdate(item)
 	opts := options.Update().SetUpsert(true)
 	_, err := GameDownloadCollection.UpdateOne(ctx, filter, update, opts)
 	if err != nil {
+		if item.TorrentFileID != oldTorrentFileID {
+			_ = DeleteTorrentFile(ctx, item.TorrentFileID)
+			item.TorrentFileID = oldTorrentFileID
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateInfoHash
+		}
 		return err
 	}
+	if revision != nil {
+		if err := SaveGameDownloadRevision(ctx, revision); err != nil {
+			log.Logger.Warn("Failed to save game download revision", zap.String("url", item.Url), zap.Error(err))
+		} else if revision.TorrentFileID == oldTorrentFileID {
+			// the revision still references the replaced .torrent file
+			return nil
+		}
+	}
+	if item.TorrentFileID != oldTorrentFileID && !oldTorrentFileID.IsZero() {
+		if err := DeleteTorrentFile(ctx, oldTorrentFileID); err != nil {
+			log.Logger.Warn("Failed to delete torrent file", zap.Error(err))
+		}
+	}
 	return nil
 }
 
-func SaveGameInfo(item *model.GameInfo) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func SaveGameInfo(ctx context.Context, item *model.GameInfo) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	if item.ID.IsZero() {
 		item.ID = primitive.NewObjectID()
@@ -128,8 +277,21 @@ func SaveGameInfo(item *model.GameInfo) error {
 	return nil
 }
 
-func SaveGameDownloads(items []*model.GameDownload) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// UpdateGameInfoFields sets only the given fields of a game info, so a
+// backfill does not overwrite what organize or a merge changed meanwhile.
+func UpdateGameInfoFields(ctx context.Context, id primitive.ObjectID, fields bson.D) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	fields = append(fields, bson.E{Key: "updated_at", Value: time.Now()})
+	_, err := GameInfoCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func SaveGameDownloads(ctx context.Context, items []*model.GameDownload) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	operations := []mongo.WriteModel{}
 	for _, item := range items {
@@ -140,8 +302,11 @@ func SaveGameDownloads(items []*model.GameDownload) error {
 			item.CreatedAt = time.Now()
 		}
 		item.UpdatedAt = time.Now()
+		SetMagnetFields(item)
+		SetSizeBytes(item)
+		item.Release = utils.ParseRelease(item.RawName)
 		filter := bson.M{"_id": item.ID}
-		update := bson.M{"$set": item}
+		update := gameDownloadUpdate(item)
 		model := mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true)
 		operations = append(operations, model)
 	}
@@ -152,9 +317,9 @@ func SaveGameDownloads(items []*model.GameDownload) error {
 	return nil
 }
 
-func GetAllGameDownloads() ([]*model.GameDownload, error) {
+func GetAllGameDownloads(ctx context.Context) ([]*model.GameDownload, error) {
 	var items []*model.GameDownload
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	cursor, err := GameDownloadCollection.Find(ctx, bson.D{})
 	if err != nil {
@@ -174,9 +339,9 @@ func GetAllGameDownloads() ([]*model.GameDownload, error) {
 	return items, err
 }
 
-func GetGameDownloadByUrl(url string) (*model.GameDownload, error) {
+func GetGameDownloadByUrl(ctx context.Context, url string) (*model.GameDownload, error) {
 	var item model.GameDownload
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	filter := bson.M{"url": url}
 	err := GameDownloadCollection.FindOne(ctx, filter).Decode(&item)
@@ -189,9 +354,9 @@ func GetGameDownloadByUrl(url string) (*model.GameDownload, error) {
 	return &item, nil
 }
 
-func GetGameDownloadByID(id primitive.ObjectID) (*model.GameDownload, error) {
+func GetGameDownloadByID(ctx context.Context, id primitive.ObjectID) (*model.GameDownload, error) {
 	var item model.GameDownload
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	filter := bson.M{"_id": id}
 	err := GameDownloadCollection.FindOne(ctx, filter).Decode(&item)
@@ -204,9 +369,9 @@ func GetGameDownloadByID(id primitive.ObjectID) (*model.GameDownload, error) {
 	return &item, nil
 }
 
-func GetGameDownloadsByIDs(ids []primitive.ObjectID) ([]*model.GameDownload, error) {
+func GetGameDownloadsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*model.GameDownload, error) {
 	var items []*model.GameDownload
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	cursor, err := GameDownloadCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
 	if err != nil {
@@ -226,56 +391,208 @@ func GetGameDownloadsByIDs(ids []primitive.ObjectID) ([]*model.GameDownload, err
 	return items, err
 }
 
-func SearchGameInfos(name string, page int, pageSize int) ([]*model.GameInfo, int, error) {
-	var items []*model.GameInfo
+const (
+	SearchSortName         = "name"
+	SearchSortSize         = "size"
+	SearchSortSizeDesc     = "-size"
+	SearchSortPlaytime     = "playtime"
+	SearchSortPlaytimeDesc = "-playtime"
+)
+
+// SearchOptions filters and sorts the results of SearchGameInfos. MinSize and
+// MaxSize are in bytes and only keep the game downloads within the range,
+// game infos without any are left out. Sorting by size uses the smallest
+// (or largest for SearchSortSizeDesc) game download of each game info,
+// downloads of unknown size go last.
+// Sorting by playtime uses the main story playtime, game infos without one go
+// last. Game infos have to have all given genres, themes, game modes,
+// perspectives and franchises, a rating of at least MinRating and a release
+// year within MinYear and MaxYear.
+type SearchOptions struct {
+	MinSize      int64
+	MaxSize      int64
+	Sort         string
+	Genres       []string
+	Themes       []string
+	GameModes    []string
+	Perspectives []string
+	Franchises   []string
+	MinRating    float64
+	MinYear      int
+	MaxYear      int
+}
+
+func (o SearchOptions) infoFilter(filter bson.M) {
+	for field, values := range map[string][]string{
+		"genres":              o.Genres,
+		"themes":              o.Themes,
+		"game_modes":          o.GameModes,
+		"player_perspectives": o.Perspectives,
+		"franchises":          o.Franchises,
+	} {
+		if len(values) > 0 {
+			filter[field] = bson.M{"$all": values}
+		}
+	}
+	if o.MinRating > 0 {
+		filter["rating"] = bson.M{"$gte": o.MinRating}
+	}
+	releaseDate := bson.M{}
+	if o.MinYear > 0 {
+		releaseDate["$gte"] = time.Date(o.MinYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if o.MaxYear > 0 {
+		releaseDate["$lt"] = time.Date(o.MaxYear+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if len(releaseDate) > 0 {
+		filter["release_date"] = releaseDate
+	}
+}
+
+func (o SearchOptions) sizeFilter() bson.M {
+	filter := bson.M{}
+	if o.MinSize > 0 {
+		filter["$gte"] = o.MinSize
+	}
+	if o.MaxSize > 0 {
+		filter["$lte"] = o.MaxSize
+	}
+	return filter
+}
+
+func SearchGameInfos(ctx context.Context, name string, page int, pageSize int, opts SearchOptions) ([]*model.GameInfo, int, error) {
 	name = removeDelimiter.ReplaceAllString(name, " ")
 	name = removeRepeatingSpacesRegex.ReplaceAllString(name, " ")
 	name = strings.TrimSpace(name)
 	name = strings.Replace(name, " ", ".*", -1)
 	name = fmt.Sprintf("%s.*", name)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	filter := bson.M{"$or": []interface{}{
-		bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}},
-		bson.M{"aliases": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}},
-	}}
-	totalCount, err := GameInfoCollection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
+	filter := bson.M{}
+	if name != ".*" {
+		filter["$or"] = []interface{}{
+			bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}},
+			bson.M{"aliases": bson.M{"$regex": primitive.Regex{Pattern: name, Options: "i"}}},
+		}
 	}
-	totalPages := (totalCount + int64(pageSize) - 1) / int64(pageSize)
-	findOpts := options.Find().SetSkip(int64((page - 1) * pageSize)).SetLimit(int64(pageSize)).SetSort(bson.D{{Key: "name", Value: 1}})
+	opts.infoFilter(filter)
+	downloadsMatch := bson.M{"$expr": bson.M{"$in": bson.A{"$_id", "$$ids"}}}
+	sizeFilter := opts.sizeFilter()
+	if len(sizeFilter) > 0 {
+		downloadsMatch["size_bytes"] = sizeFilter
+	}
+	// downloads of unknown size go last
+	downloadsOrder := 1
+	downloadSize := bson.M{"$ifNull": bson.A{"$size_bytes", math.MaxInt64}}
+	if opts.Sort == SearchSortSizeDesc {
+		downloadsOrder = -1
+		downloadSize = bson.M{"$ifNull": bson.A{"$size_bytes", -1}}
+	}
+	lookup := bson.D{{Key: "$lookup", Value: bson.D{
+		{Key: "from", Value: GameDownloadCollection.Name()},
+		{Key: "let", Value: bson.M{"ids": bson.M{"$ifNull": bson.A{"$games", bson.A{}}}}},
+		{Key: "pipeline", Value: bson.A{
+			bson.M{"$match": downloadsMatch},
+			bson.M{"$addFields": bson.M{"sort_size": downloadSize}},
+			bson.M{"$sort": bson.D{{Key: "sort_size", Value: downloadsOrder}}},
+			bson.M{"$project": bson.M{"sort_size": 0}},
+		}},
+		{Key: "as", Value: "downloads"},
+	}}}
 
-	cursor, err := GameInfoCollection.Find(ctx, filter, findOpts)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer cursor.Close(ctx)
-	for cursor.Next(ctx) {
-		var game model.GameInfo
-		if err = cursor.Decode(&game); err != nil {
-			return nil, 0, err
+	pipeline := mongo.Pipeline{bson.D{{Key: "$match", Value: filter}}}
+	items := mongo.Pipeline{
+		bson.D{{Key: "$skip", Value: int64((page - 1) * pageSize)}},
+		bson.D{{Key: "$limit", Value: int64(pageSize)}},
+	}
+	switch {
+	case opts.Sort == SearchSortSize || opts.Sort == SearchSortSizeDesc:
+		pipeline = append(pipeline, lookup)
+		if len(sizeFilter) > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"downloads": bson.M{"$ne": bson.A{}}}}})
 		}
-		game.Games, err = GetGameDownloadsByIDs(game.GameIDs)
-		if err != nil {
-			return nil, 0, err
+		// game infos without sized downloads go last
+		sortSize := bson.M{"$ifNull": bson.A{bson.M{"$min": "$downloads.size_bytes"}, math.MaxInt64}}
+		if opts.Sort == SearchSortSizeDesc {
+			sortSize = bson.M{"$ifNull": bson.A{bson.M{"$max": "$downloads.size_bytes"}, -1}}
 		}
-		items = append(items, &game)
+		pipeline = append(pipeline,
+			bson.D{{Key: "$addFields", Value: bson.M{"sort_size": sortSize}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "sort_size", Value: downloadsOrder}, {Key: "name", Value: 1}}}},
+		)
+	case opts.Sort == SearchSortPlaytime || opts.Sort == SearchSortPlaytimeDesc:
+		if len(sizeFilter) > 0 {
+			pipeline = append(pipeline,
+				lookup,
+				bson.D{{Key: "$match", Value: bson.M{"downloads": bson.M{"$ne": bson.A{}}}}},
+			)
+		} else {
+			items = append(items, lookup)
+		}
+		sortPlaytime := bson.M{"$ifNull": bson.A{"$playtime.main", math.MaxFloat64}}
+		playtimeOrder := 1
+		if opts.Sort == SearchSortPlaytimeDesc {
+			sortPlaytime = bson.M{"$ifNull": bson.A{"$playtime.main", -1}}
+			playtimeOrder = -1
+		}
+		pipeline = append(pipeline,
+			bson.D{{Key: "$addFields", Value: bson.M{"sort_playtime": sortPlaytime}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "sort_playtime", Value: playtimeOrder}, {Key: "name", Value: 1}}}},
+		)
+	case len(sizeFilter) > 0:
+		pipeline = append(pipeline,
+			lookup,
+			bson.D{{Key: "$match", Value: bson.M{"downloads": bson.M{"$ne": bson.A{}}}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}}}},
+		)
+	default:
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}}}})
+		items = append(items, lookup)
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"total": bson.A{bson.M{"$count": "count"}},
+		"items": items,
+	}}})
+
+	type searchResult struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Items []struct {
+			model.GameInfo `bson:",inline"`
+			Downloads      []*model.GameDownload `bson:"downloads"`
+		} `bson:"items"`
+	}
+	cursor, err := GameInfoCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
 	}
-	if err := cursor.Err(); err != nil {
+	var res []searchResult
+	if err = cursor.All(ctx, &res); err != nil {
 		return nil, 0, err
 	}
-	return items, int(totalPages), nil
+	if len(res) == 0 || len(res[0].Total) == 0 {
+		return nil, 0, nil
+	}
+	totalCount := res[0].Total[0].Count
+	totalPages := (totalCount + int64(pageSize) - 1) / int64(pageSize)
+	infos := make([]*model.GameInfo, 0, len(res[0].Items))
+	for _, item := range res[0].Items {
+		info := item.GameInfo
+		info.Games = item.Downloads
+		infos = append(infos, &info)
+	}
+	return infos, int(totalPages), nil
 }
 
-func SearchGameInfosCache(name string, page int, pageSize int) ([]*model.GameInfo, int, error) {
+func SearchGameInfosCache(ctx context.Context, name string, page int, pageSize int, opts SearchOptions) ([]*model.GameInfo, int, error) {
 	type res struct {
 		Items     []*model.GameInfo
 		TotalPage int
 	}
 	if config.Config.RedisAvaliable {
-		key := fmt.Sprintf("searchGameDetails:%s:%d:%d", name, page, pageSize)
+		key := fmt.Sprintf("searchGameDetails:%s:%d:%d:%+v", name, page, pageSize, opts)
 		val, exist := cache.Redis.Get(key)
 		if exist {
 			var data res
@@ -285,7 +602,7 @@ func SearchGameInfosCache(name string, page int, pageSize int) ([]*model.GameInf
 			}
 			return data.Items, data.TotalPage, nil
 		} else {
-			data, totalPage, err := SearchGameInfos(name, page, pageSize)
+			data, totalPage, err := SearchGameInfos(ctx, name, page, pageSize, opts)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -300,12 +617,12 @@ func SearchGameInfosCache(name string, page int, pageSize int) ([]*model.GameInf
 			return data, totalPage, nil
 		}
 	} else {
-		return SearchGameInfos(name, page, pageSize)
+		return SearchGameInfos(ctx, name, page, pageSize, opts)
 	}
 }
 
-func GetGameInfoByPlatformID(idtype string, id int) (*model.GameInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func GetGameInfoByPlatformID(ctx context.Context, idtype string, id int) (*model.GameInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	var filter interface{}
 	switch idtype {
@@ -324,8 +641,8 @@ func GetGameInfoByPlatformID(idtype string, id int) (*model.GameInfo, error) {
 	return &game, nil
 }
 
-func IsGameInfoExist(idtype string, id int) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func IsGameInfoExist(ctx context.Context, idtype string, id int) bool {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	var filter interface{}
 	switch idtype {
@@ -348,11 +665,12 @@ func IsGameInfoExist(idtype string, id int) bool {
 	return true
 }
 
-func GetGameDownloadsNotInGameInfos(num int) ([]*model.GameDownload, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func GetGameDownloadsNotInGameInfos(ctx context.Context, num int) ([]*model.GameDownload, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	var gamesNotInDetails []*model.GameDownload
 	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"review.status": bson.M{"$ne": model.ReviewStatusNotAGame}}}},
 		bson.D{{Key: "$lookup", Value: bson.D{
 			{Key: "from", Value: "game_infos"},
 			{Key: "localField", Value: "_id"},
@@ -391,8 +709,8 @@ func GetGameDownloadsNotInGameInfos(num int) ([]*model.GameDownload, error) {
 	return gamesNotInDetails, nil
 }
 
-func GetGameInfoByID(id primitive.ObjectID) (*model.GameInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func GetGameInfoByID(ctx context.Context, id primitive.ObjectID) (*model.GameInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	var game model.GameInfo
 	err := GameInfoCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&game)
@@ -402,20 +720,103 @@ func GetGameInfoByID(id primitive.ObjectID) (*model.GameInfo, error) {
 	return &game, nil
 }
 
-func DeduplicateGames() error {
+// SaveGameDownloadMatch stores how a game download was matched.
+func SaveGameDownloadMatch(ctx context.Context, id primitive.ObjectID, match *model.MatchResult) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, err := GameDownloadCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"match": match}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnlinkGameDownloads removes game downloads from the game info infoID, or
+// from all game infos they are linked to if infoID is nil, so they are matched
+// again by organize.
+func UnlinkGameDownloads(ctx context.Context, ids []primitive.ObjectID, infoID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	filter := bson.M{"games": bson.M{"$in": ids}}
+	if !infoID.IsZero() {
+		filter["_id"] = infoID
+	}
+	update := bson.M{"$pull": bson.M{"games": bson.M{"$in": ids}}}
+	_, err := GameInfoCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// LinkGameDownload adds a game download to the game info infoID. Returns
+// false if the game info does not exist.
+func LinkGameDownload(ctx context.Context, gameID primitive.ObjectID, infoID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	update := bson.M{
+		"$addToSet": bson.M{"games": gameID},
+		"$set":      bson.M{"updated_at": time.Now()},
+	}
+	res, err := GameInfoCollection.UpdateOne(ctx, bson.M{"_id": infoID}, update)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// IsGameDownloadLinked reports whether a game download is in any game info.
+func IsGameDownloadLinked(ctx context.Context, gameID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	count, err := GameInfoCollection.CountDocuments(ctx, bson.M{"games": gameID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ResetGameDownloadMatch removes the match and review of a game download so
+// organize and the review queue handle it like a new one. A review marking it
+// as not a game is kept.
+func ResetGameDownloadMatch(ctx context.Context, gameID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	update := bson.A{
+		bson.M{"$unset": "match"},
+		bson.M{"$set": bson.M{"review": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$review.status", model.ReviewStatusNotAGame}},
+			"$review",
+			"$$REMOVE",
+		}}}},
+	}
+	_, err := GameDownloadCollection.UpdateOne(ctx, bson.M{"_id": gameID}, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeduplicateGames removes game downloads of the same torrent, grouped by
+// info-hash or by magnet if it could not be parsed, keeping the oldest one.
+func DeduplicateGames(ctx context.Context) error {
 	type queryRes struct {
 		ID    string               `bson:"_id"`
 		Total int                  `bson:"total"`
 		IDs   []primitive.ObjectID `bson:"ids"`
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var res []queryRes
 	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "magnet", Value: bson.D{{Key: "$exists", Value: true}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}}}},
 		bson.D{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$magnet"},
+			{Key: "_id", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$info_hash", "$magnet"}}}},
 			{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
 			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
 		}}},
@@ -431,38 +832,78 @@ func DeduplicateGames() error {
 		return err
 	}
 	for _, item := range res {
-		idsToDelete := item.IDs[1:]
-		_, err = GameDownloadCollection.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: idsToDelete}}}})
-		if err != nil {
-			return err
-		}
-		log.Logger.Info("Removed duplicates", zap.Any("ids", idsToDelete))
-		cursor, err := GameInfoCollection.Find(ctx, bson.M{"games": bson.M{"$in": idsToDelete}})
-		if err != nil {
+		if err := RemoveDuplicateGameDownloads(ctx, item.IDs[0], item.IDs[1:]); err != nil {
 			return err
 		}
-		var infos []*model.GameInfo
-		if err := cursor.All(ctx, &infos); err != nil {
-			return err
+	}
+	return nil
+}
+
+// RemoveDuplicateGameDownloads deletes the game downloads in drop and links
+// keep to the game infos that referenced them instead.
+func RemoveDuplicateGameDownloads(ctx context.Context, keep primitive.ObjectID, drop []primitive.ObjectID) error {
+	dropped, err := GetGameDownloadsByIDs(ctx, drop)
+	if err != nil {
+		return err
+	}
+	_, err = GameDownloadCollection.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: drop}}}})
+	if err != nil {
+		return err
+	}
+	for _, game := range dropped {
+		if !game.TorrentFileID.IsZero() {
+			if err := DeleteTorrentFile(ctx, game.TorrentFileID); err != nil {
+				log.Logger.Warn("Failed to delete torrent file", zap.Error(err))
+			}
 		}
-		for _, info := range infos {
-			newGames := make([]primitive.ObjectID, 0, len(info.GameIDs))
-			for _, id := range info.GameIDs {
-				if !slices.Contains(idsToDelete, id) {
-					newGames = append(newGames, id)
-				}
+	}
+	if err := DeleteGameDownloadHistory(ctx, drop); err != nil {
+		log.Logger.Warn("Failed to delete game download history", zap.Error(err))
+	}
+	log.Logger.Info("Removed duplicates", zap.String("keep", keep.Hex()), zap.Any("ids", drop))
+	cursor, err := GameInfoCollection.Find(ctx, bson.M{"games": bson.M{"$in": drop}})
+	if err != nil {
+		return err
+	}
+	var infos []*model.GameInfo
+	if err := cursor.All(ctx, &infos); err != nil {
+		return err
+	}
+	for _, info := range infos {
+		newGames := make([]primitive.ObjectID, 0, len(info.GameIDs))
+		for _, id := range info.GameIDs {
+			if slices.Contains(drop, id) {
+				id = keep
 			}
-			info.GameIDs = newGames
-			if err := SaveGameInfo(info); err != nil {
-				return err
+			if !slices.Contains(newGames, id) {
+				newGames = append(newGames, id)
 			}
 		}
+		info.GameIDs = newGames
+		if err := SaveGameInfo(ctx, info); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func GetGameInfosByName(name string) ([]*model.GameInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// CreateInfoHashIndex makes info_hash unique among game downloads. It fails
+// while duplicates exist, run DeduplicateGames first.
+func CreateInfoHashIndex(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	infoHashIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "info_hash", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"info_hash": bson.M{"$type": "string"}}),
+	}
+	_, err := GameDownloadCollection.Indexes().CreateOne(ctx, infoHashIndex)
+	return err
+}
+
+func GetGameInfosByName(ctx context.Context, name string) ([]*model.GameInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	name = strings.TrimSpace(name)
 	name = fmt.Sprintf("^%s$", name)
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+func newGameDownloadRevision(old *model.GameDownload, replacedAt time.Time) *model.GameDownloadRevision {
+	return &model.GameDownloadRevision{
+		ID:             primitive.NewObjectID(),
+		GameDownloadID: old.ID,
+		RawName:        old.RawName,
+		Magnet:         old.Magnet,
+		InfoHash:       old.InfoHash,
+		TorrentFileID:  old.TorrentFileID,
+		Files:          old.Files,
+		Size:           old.Size,
+		SizeBytes:      old.SizeBytes,
+		Release:        old.Release,
+		UpdateFlag:     old.UpdateFlag,
+		ReplacedAt:     replacedAt,
+	}
+}
+
+func SaveGameDownloadRevision(ctx context.Context, revision *model.GameDownloadRevision) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if revision.ID.IsZero() {
+		revision.ID = primitive.NewObjectID()
+	}
+	_, err := GameDownloadHistoryCollection.InsertOne(ctx, revision)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetGameDownloadHistory returns the previous releases of a game download,
+// newest first.
+func GetGameDownloadHistory(ctx context.Context, id primitive.ObjectID) ([]*model.GameDownloadRevision, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "replaced_at", Value: -1}})
+	cursor, err := GameDownloadHistoryCollection.Find(ctx, bson.M{"game_download_id": id}, opts)
+	if err != nil {
+		return nil, err
+	}
+	revisions := []*model.GameDownloadRevision{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// DeleteGameDownloadHistory deletes the previous releases of game downloads
+// and their .torrent files.
+func DeleteGameDownloadHistory(ctx context.Context, ids []primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	filter := bson.M{"game_download_id": bson.M{"$in": ids}}
+	cursor, err := GameDownloadHistoryCollection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	var revisions []*model.GameDownloadRevision
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return err
+	}
+	if _, err = GameDownloadHistoryCollection.DeleteMany(ctx, filter); err != nil {
+		return err
+	}
+	for _, revision := range revisions {
+		if !revision.TorrentFileID.IsZero() {
+			if err := DeleteTorrentFile(ctx, revision.TorrentFileID); err != nil {
+				log.Logger.Warn("Failed to delete torrent file", zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// mongoIllegalOperation is returned for transactions on a standalone server.
+const mongoIllegalOperation = 20
+
+// GetAllGameInfos returns all game infos without their descriptions.
+func GetAllGameInfos(ctx context.Context) ([]*model.GameInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	opts := options.Find().SetProjection(bson.M{"description": 0}).SetSort(bson.M{"created_at": 1})
+	cursor, err := GameInfoCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var infos []*model.GameInfo
+	if err = cursor.All(ctx, &infos); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// GetGameInfosByIDs returns the game infos with the given IDs, missing ones
+// are skipped.
+func GetGameInfosByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*model.GameInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	cursor, err := GameInfoCollection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var infos []*model.GameInfo
+	if err = cursor.All(ctx, &infos); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// MergeGameInfos replaces the game info keep and deletes the game infos in
+// drop in one transaction. Standalone servers do not support transactions,
+// there keep is saved before drop is deleted so no game download is left
+// without a game info.
+func MergeGameInfos(ctx context.Context, keep *model.GameInfo, drop []primitive.ObjectID) error {
+	keep.UpdatedAt = time.Now()
+	session, err := MongoDB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, mergeGameInfos(ctx, keep, drop)
+	})
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == mongoIllegalOperation {
+		log.Logger.Warn("Transactions are not supported, merging without one", zap.Error(err))
+		return mergeGameInfos(ctx, keep, drop)
+	}
+	return err
+}
+
+func mergeGameInfos(ctx context.Context, keep *model.GameInfo, drop []primitive.ObjectID) error {
+	if _, err := GameInfoCollection.ReplaceOne(ctx, bson.M{"_id": keep.ID}, keep); err != nil {
+		return err
+	}
+	_, err := GameInfoCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": drop}})
+	return err
+}
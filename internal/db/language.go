@@ -9,8 +9,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func SaveLanguage(language *model.Language) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func SaveLanguage(ctx context.Context, language *model.Language) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	filter := bson.M{"id": language.ID}
 	update := bson.M{"$set": language}
@@ -22,9 +22,9 @@ func SaveLanguage(language *model.Language) error {
 	return nil
 }
 
-func GetLanguages(ids []int) ([]*model.Language, error) {
+func GetLanguages(ctx context.Context, ids []int) ([]*model.Language, error) {
 	var languages []*model.Language
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	var filter interface{}
 	if len(ids) == 1 {
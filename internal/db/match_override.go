@@ -0,0 +1,82 @@
+package db
+
+import (
+	"GameDB/internal/model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetMatchOverrides returns all overrides, oldest first.
+func GetMatchOverrides(ctx context.Context) ([]*model.MatchOverride, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := MatchOverrideCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	overrides := []*model.MatchOverride{}
+	if err = cursor.All(ctx, &overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// SaveMatchOverride replaces the override of the same URL and pattern.
+func SaveMatchOverride(ctx context.Context, override *model.MatchOverride) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if override.CreatedAt.IsZero() {
+		override.CreatedAt = time.Now()
+	}
+	filter := bson.M{"url": override.URL, "pattern": override.Pattern}
+	update := bson.M{
+		"$set": bson.M{
+			"id_type": override.IDType,
+			"id":      override.PlatformID,
+			"note":    override.Note,
+		},
+		"$setOnInsert": bson.M{"created_at": override.CreatedAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return MatchOverrideCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(override)
+}
+
+func DeleteMatchOverride(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	res, err := MatchOverrideCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// EachGameDownloadName streams the ID, URL and raw name of the game
+// downloads, only of the given URLs if urls is not nil, to fn.
+func EachGameDownloadName(ctx context.Context, urls []string, fn func(item *model.GameDownload)) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	filter := bson.M{}
+	if urls != nil {
+		filter = bson.M{"url": bson.M{"$in": urls}}
+	}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "url": 1, "raw_name": 1})
+	cursor, err := GameDownloadCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var item model.GameDownload
+		if err := cursor.Decode(&item); err != nil {
+			return err
+		}
+		fn(&item)
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"GameDB/internal/model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveReferences stores IGDB references like genres in one of the reference
+// collections, e.g. GenreCollection.
+func SaveReferences(ctx context.Context, collection *mongo.Collection, references []*model.Reference) error {
+	if len(references) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var operations []mongo.WriteModel
+	for _, reference := range references {
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"id": reference.RID}).
+			SetUpdate(bson.M{"$set": reference}).
+			SetUpsert(true))
+	}
+	_, err := collection.BulkWrite(ctx, operations)
+	return err
+}
+
+// GetReferences returns the references with the IGDB IDs ids from a
+// reference collection, missing ones are skipped.
+func GetReferences(ctx context.Context, collection *mongo.Collection, ids []int) ([]*model.Reference, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	cursor, err := collection.Find(ctx, bson.M{"id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	var references []*model.Reference
+	if err = cursor.All(ctx, &references); err != nil {
+		return nil, err
+	}
+	return references, nil
+}
+
+// GetReferenceNames returns the names of all references of a reference
+// collection sorted by name.
+func GetReferenceNames(ctx context.Context, collection *mongo.Collection) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	opts := options.Find().SetSort(bson.M{"name": 1}).SetProjection(bson.M{"name": 1})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var references []*model.Reference
+	if err = cursor.All(ctx, &references); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(references))
+	for _, reference := range references {
+		names = append(names, reference.Name)
+	}
+	return names, nil
+}
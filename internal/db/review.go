@@ -0,0 +1,59 @@
+package db
+
+import (
+	"GameDB/internal/model"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetReviewQueue returns the game downloads without a review that are not
+// linked to a game info or were matched with a confidence under
+// minConfidence, newest first.
+func GetReviewQueue(ctx context.Context, minConfidence float64, limit int) ([]*model.GameDownload, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"review": bson.M{"$exists": false}}}},
+		bson.D{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "game_infos"},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "games"},
+			{Key: "pipeline", Value: bson.A{bson.D{{Key: "$project", Value: bson.M{"_id": 1}}}}},
+			{Key: "as", Value: "infos"},
+		}}},
+		bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"infos": bson.M{"$size": 0}},
+			bson.M{"match.id": bson.M{"$gt": 0}, "match.confidence": bson.M{"$lt": minConfidence}},
+		}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}}}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+	cursor, err := GameDownloadCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.GameDownload
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func SaveGameDownloadReview(ctx context.Context, id primitive.ObjectID, review *model.Review) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if review.ReviewedAt.IsZero() {
+		review.ReviewedAt = time.Now()
+	}
+	_, err := GameDownloadCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"review": review}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
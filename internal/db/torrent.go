@@ -0,0 +1,96 @@
+package db
+
+import (
+	"GameDB/internal/model"
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	if d, ok := ctx.Deadline(); ok {
+		return d
+	}
+	return time.Now().Add(timeout)
+}
+
+// SaveTorrentFile stores a .torrent file of a game download in GridFS.
+func SaveTorrentFile(ctx context.Context, gameDownloadID primitive.ObjectID, filename string, data []byte) (primitive.ObjectID, error) {
+	opts := options.GridFSUpload().SetMetadata(bson.M{"game_download_id": gameDownloadID})
+	stream, err := TorrentBucket.OpenUploadStream(filename, opts)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if err = stream.SetWriteDeadline(deadline(ctx, 30*time.Second)); err != nil {
+		_ = stream.Abort()
+		return primitive.NilObjectID, err
+	}
+	if _, err = io.Copy(stream, bytes.NewReader(data)); err != nil {
+		_ = stream.Abort()
+		return primitive.NilObjectID, err
+	}
+	if err = stream.Close(); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return stream.FileID.(primitive.ObjectID), nil
+}
+
+// GetTorrentFile returns the name and content of a stored .torrent file.
+func GetTorrentFile(ctx context.Context, id primitive.ObjectID) (string, []byte, error) {
+	stream, err := TorrentBucket.OpenDownloadStream(id)
+	if err != nil {
+		return "", nil, err
+	}
+	defer stream.Close()
+	if err = stream.SetReadDeadline(deadline(ctx, 30*time.Second)); err != nil {
+		return "", nil, err
+	}
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return "", nil, err
+	}
+	return stream.GetFile().Name, data, nil
+}
+
+// GetGameDownloadsWithoutFiles returns game downloads that have a stored
+// .torrent file but no file list.
+func GetGameDownloadsWithoutFiles(ctx context.Context) ([]*model.GameDownload, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	filter := bson.M{
+		"torrent_file_id": bson.M{"$exists": true},
+		"files":           bson.M{"$exists": false},
+	}
+	cursor, err := GameDownloadCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.GameDownload
+	if err = cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CountGameDownloadsWithoutTorrentFile counts game downloads that have no file
+// list and no stored .torrent file to read it from.
+func CountGameDownloadsWithoutTorrentFile(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	filter := bson.M{
+		"torrent_file_id": bson.M{"$exists": false},
+		"files":           bson.M{"$exists": false},
+	}
+	return GameDownloadCollection.CountDocuments(ctx, filter)
+}
+
+func DeleteTorrentFile(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return TorrentBucket.DeleteContext(ctx, id)
+}
@@ -1,13 +0,0 @@
-package db
-
-import (
-	"GameDB/internal/model"
-)
-
-func GetXatabGameDownloads() ([]*model.GameDownload, error) {
-	return GetAllGameDownloadsWithAuthor("xatab")
-}
-
-func IsXatabCrawled(flag string) bool {
-	return IsGameCrawled(flag, "xatab")
-}
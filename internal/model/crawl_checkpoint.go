@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// CrawlCheckpoint is the progress of the last full crawl of a source.
+type CrawlCheckpoint struct {
+	Source      string    `json:"source" bson:"_id"`
+	LastPage    int       `json:"last_page" bson:"last_page"`
+	TotalPages  int       `json:"total_pages" bson:"total_pages"`
+	FailedPages []int     `json:"failed_pages,omitempty" bson:"failed_pages,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CrawlRunTypeCrawl    = "crawl"
+	CrawlRunTypeOrganize = "organize"
+
+	CrawlRunStatusRunning  = "running"
+	CrawlRunStatusSuccess  = "success"
+	CrawlRunStatusPartial  = "partial"
+	CrawlRunStatusFailed   = "failed"
+	CrawlRunStatusCanceled = "canceled"
+)
+
+type CrawlRun struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Type        string             `json:"type" bson:"type"`
+	Source      string             `json:"source,omitempty" bson:"source,omitempty"`
+	Pages       []int              `json:"pages,omitempty" bson:"pages,omitempty"`
+	FailedPages []int              `json:"failed_pages,omitempty" bson:"failed_pages,omitempty"`
+	Status      string             `json:"status" bson:"status"`
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`
+	Found       int                `json:"found" bson:"found"`
+	Skipped     int                `json:"skipped" bson:"skipped"`
+	Saved       int                `json:"saved" bson:"saved"`
+	Matched     int                `json:"matched" bson:"matched"`
+	Failed      int                `json:"failed" bson:"failed"`
+	Failures    []CrawlFailure     `json:"failures,omitempty" bson:"failures,omitempty"`
+	StartedAt   time.Time          `json:"started_at" bson:"started_at"`
+	FinishedAt  time.Time          `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+type CrawlFailure struct {
+	Url    string `json:"url,omitempty" bson:"url,omitempty"`
+	Reason string `json:"reason" bson:"reason"`
+}
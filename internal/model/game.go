@@ -18,6 +18,17 @@ type GameInfo struct {
 	SteamID         int                  `json:"-" bson:"steam_id,omitempty"`
 	GOGID           int                  `json:"-" bson:"gog_id,omitempty"`
 	HowLongToBeatID int                  `json:"-" bson:"how_long_to_beat_id,omitempty"`
+	StoreIDs        map[string]string    `json:"store_ids,omitempty" bson:"store_ids,omitempty"`
+	Playtime        *Playtime            `json:"playtime,omitempty" bson:"playtime,omitempty"`
+	Genres          []string             `json:"genres,omitempty" bson:"genres,omitempty"`
+	Themes          []string             `json:"themes,omitempty" bson:"themes,omitempty"`
+	GameModes       []string             `json:"game_modes,omitempty" bson:"game_modes,omitempty"`
+	Perspectives    []string             `json:"player_perspectives,omitempty" bson:"player_perspectives,omitempty"`
+	Franchises      []string             `json:"franchises,omitempty" bson:"franchises,omitempty"`
+	ReleaseDate     *time.Time           `json:"release_date,omitempty" bson:"release_date,omitempty"`
+	Rating          float64              `json:"rating,omitempty" bson:"rating,omitempty"`
+	RatingCount     int                  `json:"rating_count,omitempty" bson:"rating_count,omitempty"`
+	Steam           *SteamInfo           `json:"steam,omitempty" bson:"steam,omitempty"`
 	Cover           string               `json:"cover,omitempty" bson:"cover,omitempty"`
 	Languages       []string             `json:"languages,omitempty" bson:"languages,omitempty"`
 	Screenshots     []string             `json:"screenshots,omitempty" bson:"screenshots,omitempty"`
@@ -44,16 +55,71 @@ func (g *GameInfo) MarshalJSON() ([]byte, error) {
 }
 
 type GameDownload struct {
-	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Name       string             `json:"-" bson:"name,omitempty"`
-	RawName    string             `json:"raw_name,omitempty" bson:"raw_name,omitempty"`
-	Magnet     string             `json:"download_link,omitempty" bson:"magnet,omitempty"`
-	Size       string             `json:"size,omitempty" bson:"size,omitempty"`
-	Url        string             `json:"url" bson:"url,omitempty"`
-	Author     string             `json:"author,omitempty" bson:"author,omitempty"`
-	UpdateFlag string             `json:"-" bson:"update_flag,omitempty"`
-	CreatedAt  time.Time          `json:"-" bson:"created_at,omitempty"`
-	UpdatedAt  time.Time          `json:"-" bson:"updated_at,omitempty"`
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"-" bson:"name,omitempty"`
+	RawName     string             `json:"raw_name,omitempty" bson:"raw_name,omitempty"`
+	Magnet      string             `json:"download_link,omitempty" bson:"magnet,omitempty"`
+	InfoHash    string             `json:"info_hash,omitempty" bson:"info_hash,omitempty"`
+	DisplayName string             `json:"display_name,omitempty" bson:"display_name,omitempty"`
+	Trackers    []string           `json:"trackers,omitempty" bson:"trackers,omitempty"`
+	// Torrent is the .torrent file the magnet was made from, SaveGameDownload
+	// stores it in GridFS as TorrentFileID
+	Torrent       []byte             `json:"-" bson:"-"`
+	TorrentFileID primitive.ObjectID `json:"-" bson:"torrent_file_id,omitempty"`
+	Files         []TorrentFile      `json:"files,omitempty" bson:"files,omitempty"`
+	Size          string             `json:"size,omitempty" bson:"size,omitempty"`
+	SizeBytes     int64              `json:"size_bytes,omitempty" bson:"size_bytes,omitempty"`
+	Release       *ReleaseInfo       `json:"release,omitempty" bson:"release,omitempty"`
+	Match         *MatchResult       `json:"match,omitempty" bson:"match,omitempty"`
+	Review        *Review            `json:"review,omitempty" bson:"review,omitempty"`
+	Url           string             `json:"url" bson:"url,omitempty"`
+	Author        string             `json:"author,omitempty" bson:"author,omitempty"`
+	UpdateFlag    string             `json:"-" bson:"update_flag,omitempty"`
+	CreatedAt     time.Time          `json:"-" bson:"created_at,omitempty"`
+	UpdatedAt     time.Time          `json:"-" bson:"updated_at,omitempty"`
+}
+
+// Playtime is how many hours a game takes to beat according to HowLongToBeat.
+type Playtime struct {
+	Main          float64 `json:"main,omitempty" bson:"main,omitempty"`
+	MainExtra     float64 `json:"main_extra,omitempty" bson:"main_extra,omitempty"`
+	Completionist float64 `json:"completionist,omitempty" bson:"completionist,omitempty"`
+}
+
+// SteamInfo is the store page metadata of a game on Steam. Requirements are
+// keyed by their label, e.g. "Processor".
+type SteamInfo struct {
+	Genres            []string           `json:"genres,omitempty" bson:"genres,omitempty"`
+	Categories        []string           `json:"categories,omitempty" bson:"categories,omitempty"`
+	ControllerSupport string             `json:"controller_support,omitempty" bson:"controller_support,omitempty"`
+	Platforms         []string           `json:"platforms,omitempty" bson:"platforms,omitempty"`
+	Metacritic        int                `json:"metacritic,omitempty" bson:"metacritic,omitempty"`
+	Recommendations   int                `json:"recommendations,omitempty" bson:"recommendations,omitempty"`
+	Requirements      *SteamRequirements `json:"requirements,omitempty" bson:"requirements,omitempty"`
+	Trailers          []string           `json:"trailers,omitempty" bson:"trailers,omitempty"`
+	ReleaseDate       *time.Time         `json:"release_date,omitempty" bson:"release_date,omitempty"`
+	ComingSoon        bool               `json:"coming_soon,omitempty" bson:"coming_soon,omitempty"`
+}
+
+type SteamRequirements struct {
+	Minimum     map[string]string `json:"minimum,omitempty" bson:"minimum,omitempty"`
+	Recommended map[string]string `json:"recommended,omitempty" bson:"recommended,omitempty"`
+}
+
+// ReleaseInfo is parsed from the raw name of a game download.
+type ReleaseInfo struct {
+	Version    string `json:"version,omitempty" bson:"version,omitempty"`
+	Build      string `json:"build,omitempty" bson:"build,omitempty"`
+	DLCs       int    `json:"dlcs,omitempty" bson:"dlcs,omitempty"`
+	Edition    string `json:"edition,omitempty" bson:"edition,omitempty"`
+	Languages  int    `json:"languages,omitempty" bson:"languages,omitempty"`
+	Portable   bool   `json:"portable,omitempty" bson:"portable,omitempty"`
+	UpdateOnly bool   `json:"update_only,omitempty" bson:"update_only,omitempty"`
+}
+
+type TorrentFile struct {
+	Path   string `json:"path" bson:"path"`
+	Length int64  `json:"length" bson:"length"`
 }
 
 type Language struct {
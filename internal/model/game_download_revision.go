@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GameDownloadRevision is a previous release of a game download that was
+// replaced by a newer one on the same page.
+type GameDownloadRevision struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	GameDownloadID primitive.ObjectID `json:"game_download_id" bson:"game_download_id"`
+	RawName        string             `json:"raw_name,omitempty" bson:"raw_name,omitempty"`
+	Magnet         string             `json:"download_link,omitempty" bson:"magnet,omitempty"`
+	InfoHash       string             `json:"info_hash,omitempty" bson:"info_hash,omitempty"`
+	TorrentFileID  primitive.ObjectID `json:"-" bson:"torrent_file_id,omitempty"`
+	Files          []TorrentFile      `json:"files,omitempty" bson:"files,omitempty"`
+	Size           string             `json:"size,omitempty" bson:"size,omitempty"`
+	SizeBytes      int64              `json:"size_bytes,omitempty" bson:"size_bytes,omitempty"`
+	Release        *ReleaseInfo       `json:"release,omitempty" bson:"release,omitempty"`
+	UpdateFlag     string             `json:"-" bson:"update_flag,omitempty"`
+	// ReplacedAt is when a newer release replaced this one, the release was
+	// published when the previous revision was replaced
+	ReplacedAt time.Time `json:"replaced_at" bson:"replaced_at"`
+}
@@ -150,3 +150,32 @@ type IGDBCompany struct {
 }
 
 type IGDBCompanies []*IGDBCompany
+
+type IGDBExternalGame struct {
+	ID                 int    `json:"id,omitempty"`
+	Category           int    `json:"category,omitempty"`
+	ExternalGameSource int    `json:"external_game_source,omitempty"`
+	Game               int    `json:"game,omitempty"`
+	Name               string `json:"name,omitempty"`
+	UID                string `json:"uid,omitempty"`
+	URL                string `json:"url,omitempty"`
+	Checksum           string `json:"checksum,omitempty"`
+}
+
+// Source returns the store of the external game. IGDB replaced category with
+// external_game_source, which uses the same IDs.
+func (e *IGDBExternalGame) Source() int {
+	if e.ExternalGameSource != 0 {
+		return e.ExternalGameSource
+	}
+	return e.Category
+}
+
+type IGDBExternalGames []*IGDBExternalGame
+
+type IGDBReference struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type IGDBReferences []*IGDBReference
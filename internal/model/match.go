@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// MatchResult records how a game download was matched with a game of a
+// metadata source. ID is 0 if no candidate was good enough.
+type MatchResult struct {
+	Source     string           `json:"source" bson:"source"`
+	Query      string           `json:"query" bson:"query"`
+	ID         int              `json:"id,omitempty" bson:"id,omitempty"`
+	Name       string           `json:"name,omitempty" bson:"name,omitempty"`
+	Confidence float64          `json:"confidence" bson:"confidence"`
+	Candidates []MatchCandidate `json:"candidates,omitempty" bson:"candidates,omitempty"`
+	MatchedAt  time.Time        `json:"matched_at" bson:"matched_at"`
+}
+
+const (
+	ReviewStatusAccepted = "accepted"
+	ReviewStatusNotAGame = "not_a_game"
+)
+
+// Review is the decision taken on a game download in the review queue. IDType
+// and ID are the game it was linked with if it was accepted.
+type Review struct {
+	Status     string    `json:"status" bson:"status"`
+	IDType     string    `json:"id_type,omitempty" bson:"id_type,omitempty"`
+	ID         int       `json:"id,omitempty" bson:"id,omitempty"`
+	ReviewedAt time.Time `json:"reviewed_at" bson:"reviewed_at"`
+}
+
+// MatchCandidate is a search result scored against the name of a game
+// download.
+type MatchCandidate struct {
+	ID int `json:"id" bson:"id"`
+	// MatchID is the ID linked if the candidate is accepted instead of ID,
+	// e.g. the parent game of an IGDB edition
+	MatchID int     `json:"match_id,omitempty" bson:"match_id,omitempty"`
+	Name    string  `json:"name" bson:"name"`
+	Year    int     `json:"year,omitempty" bson:"year,omitempty"`
+	Score   float64 `json:"score" bson:"score"`
+}
+
+// LinkID returns the ID a game download is linked with if the candidate is
+// accepted.
+func (c *MatchCandidate) LinkID() int {
+	if c.MatchID != 0 {
+		return c.MatchID
+	}
+	return c.ID
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchOverrideIgnore is the IDType of overrides that keep game downloads
+// from being matched.
+const MatchOverrideIgnore = "ignore"
+
+// MatchOverride maps game downloads to a game, by the exact URL of their page
+// or by a regular expression matched against their raw name, so organize does
+// not search for them.
+type MatchOverride struct {
+	ID      primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	URL     string             `json:"url,omitempty" bson:"url"`
+	Pattern string             `json:"pattern,omitempty" bson:"pattern"`
+	// IDType is igdb, steam, gog or MatchOverrideIgnore
+	IDType     string    `json:"id_type" bson:"id_type"`
+	PlatformID int       `json:"id,omitempty" bson:"id,omitempty"`
+	Note       string    `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedAt  time.Time `json:"-" bson:"created_at"`
+}
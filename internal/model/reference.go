@@ -0,0 +1,10 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Reference is the name of an IGDB ID like a genre, a theme or a franchise.
+type Reference struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty"`
+	RID  int                `bson:"id,omitempty"`
+	Name string             `bson:"name,omitempty"`
+}
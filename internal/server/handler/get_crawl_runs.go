@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetCrawlRunsRequest struct {
+	Type   string `form:"type" json:"type"`
+	Source string `form:"source" json:"source"`
+	Status string `form:"status" json:"status"`
+	Limit  int    `form:"limit" json:"limit"`
+}
+
+type GetCrawlRunsResponse struct {
+	Status  string            `json:"status"`
+	Message string            `json:"message,omitempty"`
+	Runs    []*model.CrawlRun `json:"runs,omitempty"`
+}
+
+func GetCrawlRuns(c *gin.Context) {
+	var req GetCrawlRunsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetCrawlRunsResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	runs, err := db.GetCrawlRuns(c.Request.Context(), req.Type, req.Source, req.Status, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetCrawlRunsResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, GetCrawlRunsResponse{
+		Status: "ok",
+		Runs:   runs,
+	})
+}
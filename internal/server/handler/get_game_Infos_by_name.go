@@ -27,7 +27,7 @@ func GetGameInfosByName(c *gin.Context) {
 		})
 		return
 	}
-	games, err := db.GetGameInfosByName(req.Name)
+	games, err := db.GetGameInfosByName(c.Request.Context(), req.Name)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, GetGameInfosByNameResponse{
 			Status:  "error",
@@ -36,7 +36,7 @@ func GetGameDownload(c *gin.Context) {
 		})
 		return
 	}
-	game, err := db.GetGameDownloadByID(id)
+	game, err := db.GetGameDownloadByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, GetGameDownloadResponse{
 			Status:  "error",
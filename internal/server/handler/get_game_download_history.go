@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"GameDB/internal/db"
+	"GameDB/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GetGameDownloadHistoryResponse struct {
+	Status  string                        `json:"status"`
+	Message string                        `json:"message,omitempty"`
+	Game    *model.GameDownload           `json:"game,omitempty"`
+	History []*model.GameDownloadRevision `json:"history,omitempty"`
+}
+
+func GetGameDownloadHistory(c *gin.Context) {
+	var req GetGameDownloadRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameDownloadHistoryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetGameDownloadHistoryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	game, err := db.GetGameDownloadByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetGameDownloadHistoryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if game.ID.IsZero() {
+		c.JSON(http.StatusNotFound, GetGameDownloadHistoryResponse{
+			Status:  "error",
+			Message: "game download not found",
+		})
+		return
+	}
+	history, err := db.GetGameDownloadHistory(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetGameDownloadHistoryResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, GetGameDownloadHistoryResponse{
+		Status:  "ok",
+		Game:    game,
+		History: history,
+	})
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"GameDB/internal/db"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetGameDownloadTorrent(c *gin.Context) {
+	var req GetGameDownloadRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameDownloadResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GetGameDownloadResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	game, err := db.GetGameDownloadByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetGameDownloadResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if game.TorrentFileID.IsZero() {
+		c.JSON(http.StatusNotFound, GetGameDownloadResponse{
+			Status:  "error",
+			Message: "torrent file not found",
+		})
+		return
+	}
+	name, data, err := db.GetTorrentFile(c.Request.Context(), game.TorrentFileID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetGameDownloadResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "application/x-bittorrent", data)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"GameDB/internal/db"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type GetGameFiltersResponse struct {
+	Status       string   `json:"status"`
+	Message      string   `json:"message,omitempty"`
+	Genres       []string `json:"genres,omitempty"`
+	Themes       []string `json:"themes,omitempty"`
+	GameModes    []string `json:"game_modes,omitempty"`
+	Perspectives []string `json:"perspectives,omitempty"`
+	Franchises   []string `json:"franchises,omitempty"`
+}
+
+func GetGameFilters(c *gin.Context) {
+	res := GetGameFiltersResponse{Status: "ok"}
+	for _, filter := range []struct {
+		collection *mongo.Collection
+		names      *[]string
+	}{
+		{db.GenreCollection, &res.Genres},
+		{db.ThemeCollection, &res.Themes},
+		{db.GameModeCollection, &res.GameModes},
+		{db.PerspectiveCollection, &res.Perspectives},
+		{db.FranchiseCollection, &res.Franchises},
+	} {
+		names, err := db.GetReferenceNames(c.Request.Context(), filter.collection)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, GetGameFiltersResponse{
+				Status:  "error",
+				Message: err.Error(),
+			})
+			return
+		}
+		*filter.names = names
+	}
+	c.JSON(http.StatusOK, res)
+}
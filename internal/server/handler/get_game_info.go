@@ -4,6 +4,7 @@ import (
 	"GameDB/internal/db"
 	"GameDB/internal/model"
 	"net/http"
+	"sort"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,6 +14,13 @@ type GetGameInfoRequest struct {
 	ID string `uri:"id" binding:"required"`
 }
 
+// GetGameInfoQuery filters and sorts the game downloads of a game info.
+type GetGameInfoQuery struct {
+	MinSize int64  `form:"min_size" binding:"min=0"`
+	MaxSize int64  `form:"max_size" binding:"min=0"`
+	Sort    string `form:"sort" binding:"omitempty,oneof=size -size"`
+}
+
 type GetGameInfoResponse struct {
 	Status   string          `json:"status"`
 	Message  string          `json:"message,omitempty"`
@@ -28,6 +36,14 @@ func GetGameInfo(c *gin.Context) {
 		})
 		return
 	}
+	var query GetGameInfoQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, GetGameInfoResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
 	id, err := primitive.ObjectIDFromHex(req.ID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, GetGameInfoResponse{
@@ -36,7 +52,7 @@ func GetGameInfo(c *gin.Context) {
 		})
 		return
 	}
-	gameInfo, err := db.GetGameInfoByID(id)
+	gameInfo, err := db.GetGameInfoByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, GetGameInfoResponse{
 			Status:  "error",
@@ -44,7 +60,7 @@ func GetGameInfo(c *gin.Context) {
 		})
 		return
 	}
-	gameInfo.Games, err = db.GetGameDownloadsByIDs(gameInfo.GameIDs)
+	gameInfo.Games, err = db.GetGameDownloadsByIDs(c.Request.Context(), gameInfo.GameIDs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, GetGameInfoResponse{
 			Status:  "error",
@@ -52,8 +68,37 @@ func GetGameInfo(c *gin.Context) {
 		})
 		return
 	}
+	gameInfo.Games = filterGameDownloads(gameInfo.Games, query)
 	c.JSON(http.StatusOK, GetGameInfoResponse{
 		Status:   "ok",
 		GameInfo: gameInfo,
 	})
 }
+
+func filterGameDownloads(games []*model.GameDownload, query GetGameInfoQuery) []*model.GameDownload {
+	res := make([]*model.GameDownload, 0, len(games))
+	for _, game := range games {
+		if query.MinSize > 0 && game.SizeBytes < query.MinSize {
+			continue
+		}
+		if query.MaxSize > 0 && game.SizeBytes > query.MaxSize {
+			continue
+		}
+		res = append(res, game)
+	}
+	if query.Sort != db.SearchSortSize && query.Sort != db.SearchSortSizeDesc {
+		return res
+	}
+	// downloads of unknown size go last in both orders
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i].SizeBytes, res[j].SizeBytes
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		if query.Sort == db.SearchSortSizeDesc {
+			return a > b
+		}
+		return a < b
+	})
+	return res
+}
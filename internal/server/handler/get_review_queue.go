@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"GameDB/internal/config"
+	"GameDB/internal/db"
+	"GameDB/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GetReviewQueueRequest struct {
+	Confidence float64 `form:"confidence" json:"confidence" binding:"min=0,max=1"`
+	Limit      int     `form:"limit" json:"limit"`
+}
+
+type GetReviewQueueResponse struct {
+	Status  string                `json:"status"`
+	Message string                `json:"message,omitempty"`
+	Games   []*model.GameDownload `json:"games,omitempty"`
+}
+
+func GetReviewQueue(c *gin.Context) {
+	var req GetReviewQueueRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, GetReviewQueueResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Confidence == 0 {
+		req.Confidence = config.Config.ReviewConfidence
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	games, err := db.GetReviewQueue(c.Request.Context(), req.Confidence, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GetReviewQueueResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, GetReviewQueueResponse{
+		Status: "ok",
+		Games:  games,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"GameDB/internal/crawler"
 	"GameDB/internal/log"
 	"GameDB/internal/model"
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -24,7 +25,7 @@ func GetSteam250(c *gin.Context) {
 			Message: "Missing ranking type",
 		})
 	}
-	var f func() ([]model.Steam250Item, error)
+	var f func(context.Context) ([]model.Steam250Item, error)
 	switch rankingType {
 	case "top":
 		f = crawler.GetSteam250Top250Cache
@@ -41,7 +42,7 @@ func GetSteam250(c *gin.Context) {
 		})
 		return
 	}
-	m, err := f()
+	m, err := f(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, GetSteam250Response{
 			Status:  "error",
@@ -51,7 +52,7 @@ func GetSteam250(c *gin.Context) {
 	}
 	var infos []*model.GameInfo
 	for _, item := range m {
-		info, err := crawler.GenerateSteamGameInfo(item.SteamID)
+		info, err := crawler.GenerateSteamGameInfo(c.Request.Context(), item.SteamID)
 		if err != nil {
 			log.Logger.Warn("Failed to generate game info", zap.Error(err))
 			continue
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"GameDB/internal/crawler"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UnlinkGameDownloadRequest struct {
+	// GameInfoID is all game infos of the game download if empty
+	GameInfoID string `json:"game_info_id"`
+}
+
+type MoveGameDownloadRequest struct {
+	// From is all game infos of the game download if empty
+	From string `json:"from"`
+	To   string `json:"to" binding:"required"`
+}
+
+type UnlinkGameDownloadResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Orphaned bool   `json:"orphaned"`
+}
+
+func UnlinkGameDownload(c *gin.Context) {
+	var req UnlinkGameDownloadRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength != 0 {
+		reviewError(c, http.StatusBadRequest, err)
+		return
+	}
+	id, ok := reviewGameID(c)
+	if !ok {
+		return
+	}
+	infoID, ok := optionalGameInfoID(c, req.GameInfoID)
+	if !ok {
+		return
+	}
+	orphaned, err := crawler.UnlinkGameDownload(c.Request.Context(), id, infoID)
+	if err != nil {
+		reviewResult(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, UnlinkGameDownloadResponse{Status: "ok", Orphaned: orphaned})
+}
+
+func MoveGameDownload(c *gin.Context) {
+	var req MoveGameDownloadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		reviewError(c, http.StatusBadRequest, err)
+		return
+	}
+	id, ok := reviewGameID(c)
+	if !ok {
+		return
+	}
+	from, ok := optionalGameInfoID(c, req.From)
+	if !ok {
+		return
+	}
+	to, err := primitive.ObjectIDFromHex(req.To)
+	if err != nil {
+		reviewError(c, http.StatusBadRequest, err)
+		return
+	}
+	reviewResult(c, crawler.MoveGameDownload(c.Request.Context(), id, from, to))
+}
+
+func optionalGameInfoID(c *gin.Context, hex string) (primitive.ObjectID, bool) {
+	if hex == "" {
+		return primitive.NilObjectID, true
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		reviewError(c, http.StatusBadRequest, err)
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"GameDB/internal/config"
+	"GameDB/internal/crawler"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReloadFormatterRulesResponse struct {
+	Status  string   `json:"status"`
+	Message string   `json:"message,omitempty"`
+	Sources []string `json:"sources,omitempty"`
+}
+
+func ReloadFormatterRules(c *gin.Context) {
+	sources, err := crawler.LoadFormatterRules(config.Config.RulesDir)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ReloadFormatterRulesResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, ReloadFormatterRulesResponse{
+		Status:  "ok",
+		Sources: sources,
+	})
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"GameDB/internal/crawler"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AcceptReviewCandidateRequest struct {
+	// CandidateID is the best candidate if empty
+	CandidateID int `json:"candidate_id"`
+}
+
+type SetReviewIDRequest struct {
+	IDType string `json:"id_type" binding:"required,oneof=igdb steam gog"`
+	ID     int    `json:"id" binding:"required,min=1"`
+}
+
+type ReviewGameDownloadResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+func AcceptReviewCandidate(c *gin.Context) {
+	var req AcceptReviewCandidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength != 0 {
+		reviewError(c, http.StatusBadRequest, err)
+		return
+	}
+	id, ok := reviewGameID(c)
+	if !ok {
+		return
+	}
+	reviewResult(c, crawler.ReviewAcceptCandidate(c.Request.Context(), id, req.CandidateID))
+}
+
+func SetReviewID(c *gin.Context) {
+	var req SetReviewIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		reviewError(c, http.StatusBadRequest, err)
+		return
+	}
+	id, ok := reviewGameID(c)
+	if !ok {
+		return
+	}
+	reviewResult(c, crawler.ReviewAccept(c.Request.Context(), id, req.IDType, req.ID))
+}
+
+func MarkReviewNotAGame(c *gin.Context) {
+	id, ok := reviewGameID(c)
+	if !ok {
+		return
+	}
+	reviewResult(c, crawler.ReviewNotAGame(c.Request.Context(), id))
+}
+
+func reviewGameID(c *gin.Context) (primitive.ObjectID, bool) {
+	var req GetGameDownloadRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		reviewError(c, http.StatusBadRequest, err)
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(req.ID)
+	if err != nil {
+		reviewError(c, http.StatusBadRequest, err)
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+func reviewResult(c *gin.Context, err error) {
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, ReviewGameDownloadResponse{Status: "ok"})
+	case errors.Is(err, crawler.ErrGameDownloadNotFound), errors.Is(err, crawler.ErrCandidateNotFound),
+		errors.Is(err, crawler.ErrGameInfoNotFound):
+		reviewError(c, http.StatusNotFound, err)
+	default:
+		reviewError(c, http.StatusInternalServerError, err)
+	}
+}
+
+func reviewError(c *gin.Context, status int, err error) {
+	c.JSON(status, ReviewGameDownloadResponse{
+		Status:  "error",
+		Message: err.Error(),
+	})
+}
@@ -9,9 +9,26 @@ import (
 )
 
 type SearchGamesRequest struct {
-	Keyword  string `form:"keyword" json:"keyword" binding:"required,min=4,max=64"`
-	Page     int    `form:"page" json:"page"`
-	PageSize int    `form:"page_size" json:"page_size"`
+	// Keyword can be empty to browse by the filters
+	Keyword      string   `form:"keyword" json:"keyword" binding:"omitempty,min=4,max=64"`
+	Page         int      `form:"page" json:"page"`
+	PageSize     int      `form:"page_size" json:"page_size"`
+	MinSize      int64    `form:"min_size" json:"min_size" binding:"min=0"`
+	MaxSize      int64    `form:"max_size" json:"max_size" binding:"min=0"`
+	Sort         string   `form:"sort" json:"sort" binding:"omitempty,oneof=name size -size playtime -playtime"`
+	Genres       []string `form:"genre" json:"genres"`
+	Themes       []string `form:"theme" json:"themes"`
+	GameModes    []string `form:"game_mode" json:"game_modes"`
+	Perspectives []string `form:"perspective" json:"perspectives"`
+	Franchises   []string `form:"franchise" json:"franchises"`
+	MinRating    float64  `form:"min_rating" json:"min_rating" binding:"min=0,max=100"`
+	MinYear      int      `form:"min_year" json:"min_year" binding:"min=0"`
+	MaxYear      int      `form:"max_year" json:"max_year" binding:"min=0"`
+}
+
+func (r SearchGamesRequest) hasFilter() bool {
+	return len(r.Genres) > 0 || len(r.Themes) > 0 || len(r.GameModes) > 0 || len(r.Perspectives) > 0 ||
+		len(r.Franchises) > 0 || r.MinRating > 0 || r.MinYear > 0 || r.MaxYear > 0
 }
 
 type SearchGamesResponse struct {
@@ -30,6 +47,13 @@ func SearchGames(c *gin.Context) {
 		})
 		return
 	}
+	if req.Keyword == "" && !req.hasFilter() {
+		c.JSON(http.StatusBadRequest, SearchGamesResponse{
+			Status:  "error",
+			Message: "keyword or a filter is required",
+		})
+		return
+	}
 	if req.Page == 0 || req.Page < 0 {
 		req.Page = 1
 	}
@@ -39,7 +63,19 @@ func SearchGames(c *gin.Context) {
 	if req.PageSize > 10 {
 		req.PageSize = 10
 	}
-	items, totalPage, err := db.SearchGameInfos(req.Keyword, req.Page, req.PageSize)
+	items, totalPage, err := db.SearchGameInfos(c.Request.Context(), req.Keyword, req.Page, req.PageSize, db.SearchOptions{
+		MinSize:      req.MinSize,
+		MaxSize:      req.MaxSize,
+		Sort:         req.Sort,
+		Genres:       req.Genres,
+		Themes:       req.Themes,
+		GameModes:    req.GameModes,
+		Perspectives: req.Perspectives,
+		Franchises:   req.Franchises,
+		MinRating:    req.MinRating,
+		MinYear:      req.MinYear,
+		MaxYear:      req.MaxYear,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, SearchGamesResponse{
 			Status:  "error",
@@ -1,20 +1,50 @@
 package server
 
 import (
+	"GameDB/internal/config"
+	"GameDB/internal/log"
 	"GameDB/internal/server/handler"
+	"GameDB/internal/server/middleware"
+	"strings"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func initRoute(app *gin.Engine) {
-	app.Use(cors.New(cors.Config{
+	// admin routes are left out of CORS, browsers only call them from the
+	// same origin
+	allowAllOrigins := cors.New(cors.Config{
 		AllowAllOrigins: true,
-	}))
+	})
+	app.Use(func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, "/admin/") {
+			c.Next()
+			return
+		}
+		allowAllOrigins(c)
+	})
 
 	app.GET("/raw/:id", handler.GetGameDownload)
+	app.GET("/raw/:id/torrent", handler.GetGameDownloadTorrent)
+	app.GET("/raw/:id/history", handler.GetGameDownloadHistory)
 	app.GET("/game/search", handler.SearchGames)
+	app.GET("/game/filters", handler.GetGameFilters)
 	app.GET("/game/:id", handler.GetGameInfo)
 	app.GET("/game/name/:name", handler.GetGameInfosByName)
 	app.GET("/ranking/:type", handler.GetSteam250)
+
+	if config.Config.AdminToken == "" {
+		log.Logger.Warn("Admin routes are disabled, set admin_token to enable them")
+		return
+	}
+	admin := app.Group("/admin", middleware.AdminToken(config.Config.AdminToken))
+	admin.GET("/runs", handler.GetCrawlRuns)
+	admin.POST("/raw/:id/unlink", handler.UnlinkGameDownload)
+	admin.POST("/raw/:id/move", handler.MoveGameDownload)
+	admin.POST("/formatters/reload", handler.ReloadFormatterRules)
+	admin.GET("/review", handler.GetReviewQueue)
+	admin.POST("/review/:id/accept", handler.AcceptReviewCandidate)
+	admin.POST("/review/:id/set", handler.SetReviewID)
+	admin.POST("/review/:id/not-a-game", handler.MarkReviewNotAGame)
 }
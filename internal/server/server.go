@@ -5,14 +5,19 @@ import (
 	"GameDB/internal/log"
 	"GameDB/internal/server/middleware"
 	"GameDB/internal/task"
+	"context"
+	"errors"
 	"io"
+	"net"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 )
 
-func Run(addr string, autoCrawl bool) {
+func Run(ctx context.Context, addr string, autoCrawl bool) {
 	gin.SetMode(gin.ReleaseMode)
 	gin.DefaultWriter = io.Discard
 	app := gin.New()
@@ -21,17 +26,30 @@ func Run(addr string, autoCrawl bool) {
 	initRoute(app)
 	log.Logger.Info("Server running", zap.String("addr", addr))
 	if config.Config.AutoCrawl || autoCrawl {
-		go func() {
-			c := cron.New()
-			_, err := c.AddFunc("0 0 * * *", task.Crawl)
-			if err != nil {
-				log.Logger.Error("Error adding cron job", zap.Error(err))
-			}
-			c.Start()
-		}()
+		c := cron.New()
+		_, err := c.AddFunc("0 0 * * *", func() { task.Crawl(ctx) })
+		if err != nil {
+			log.Logger.Error("Error adding cron job", zap.Error(err))
+		}
+		c.Start()
+		defer c.Stop()
 	}
-	err := app.Run(addr)
-	if err != nil {
+	srv := &http.Server{
+		Addr:        addr,
+		Handler:     app,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Logger.Error("Failed to shutdown server", zap.Error(err))
+		}
+	}()
+	err := srv.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Logger.Panic("Failed to run server", zap.Error(err))
 	}
+	log.Logger.Info("Server stopped")
 }
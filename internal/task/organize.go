@@ -0,0 +1,74 @@
+package task
+
+import (
+	"GameDB/internal/crawler"
+	"GameDB/internal/db"
+	"GameDB/internal/log"
+	"GameDB/internal/model"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+func Organize(ctx context.Context, games []*model.GameDownload) {
+	run := crawler.RunFromContext(ctx)
+	overrides, err := crawler.LoadMatchOverrides(ctx)
+	if err != nil {
+		log.Logger.Error("Failed to load match overrides", zap.Error(err))
+		return
+	}
+	for _, game := range games {
+		if ctx.Err() != nil {
+			log.Logger.Warn("Organize canceled", zap.Error(ctx.Err()))
+			return
+		}
+		err := organizeGame(ctx, game, overrides)
+		if errors.Is(err, crawler.ErrIgnoredByOverride) || errors.Is(err, crawler.ErrNotAGame) {
+			log.Logger.Info("Game ignored", zap.String("name", game.Name), zap.Error(err))
+			run.Skipped()
+			continue
+		}
+		if err != nil {
+			log.Logger.Error("Failed to process game", zap.String("name", game.Name), zap.Error(err))
+			run.Failed(game.Url, err.Error())
+			continue
+		}
+		run.Matched()
+	}
+}
+
+func organizeGame(ctx context.Context, game *model.GameDownload, overrides *crawler.MatchOverrides) error {
+	// a decision taken in the review queue wins over overrides and matching
+	if game.Review != nil {
+		if game.Review.Status != model.ReviewStatusAccepted {
+			return crawler.ErrNotAGame
+		}
+		return crawler.AddGameInfoManually(ctx, game.ID, game.Review.IDType, game.Review.ID)
+	}
+	if override := overrides.Find(game); override != nil && override.IDType == model.MatchOverrideIgnore {
+		return crawler.ErrIgnoredByOverride
+	}
+	game.Match = nil
+	gameInfo, err := crawler.ProcessGameWithIGDB(ctx, game, overrides)
+	if err != nil {
+		gameInfo, err = crawler.ProcessGameWithSteam(ctx, game, overrides)
+	}
+	if err != nil {
+		gameInfo, err = crawler.ProcessGameWithGOG(ctx, game, overrides)
+	}
+	if game.Match != nil {
+		if err := db.SaveGameDownloadMatch(ctx, game.ID, game.Match); err != nil {
+			log.Logger.Warn("Failed to save match", zap.String("name", game.Name), zap.Error(err))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if gameInfo.Playtime == nil {
+		if err := crawler.SetHowLongToBeat(ctx, gameInfo); err != nil {
+			log.Logger.Warn("Failed to get playtime", zap.String("name", gameInfo.Name), zap.Error(err))
+		}
+	}
+	return db.SaveGameInfo(ctx, gameInfo)
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type CassetteMode int
+
+const (
+	// CassetteReplay serves recorded responses and fails requests that
+	// were not recorded.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends requests to the network and records them.
+	CassetteRecord
+)
+
+// Cassette is an http.RoundTripper that records request/response pairs to a
+// file and replays them, so crawlers can be tested without network access.
+// Requests are matched by method, URL and body. Identical requests are
+// replayed in the order they were recorded.
+type Cassette struct {
+	path         string
+	mode         CassetteMode
+	transport    http.RoundTripper
+	mu           sync.Mutex
+	interactions []*cassetteInteraction
+	replayed     map[*cassetteInteraction]bool
+}
+
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string `json:"method"`
+	Url    string `json:"url"`
+	Body   []byte `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// NewCassette loads the cassette at path. In record mode a missing file is
+// allowed and requests are sent with http.DefaultTransport.
+func NewCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		replayed:  map[*cassetteInteraction]bool{},
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && mode == CassetteRecord {
+			return c, nil
+		}
+		return nil, err
+	}
+	if mode == CassetteRecord {
+		// re-recording replaces the old interactions
+		return c, nil
+	}
+	if err = json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if c.mode == CassetteRecord {
+		return c.record(req, body)
+	}
+	return c.replay(req, body)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.interactions = append(c.interactions, &cassetteInteraction{
+		Request: cassetteRequest{
+			Method: req.Method,
+			Url:    req.URL.String(),
+			Body:   body,
+		},
+		Response: cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       respBody,
+		},
+	})
+	c.mu.Unlock()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var match *cassetteInteraction
+	for _, i := range c.interactions {
+		if i.Request.Method != req.Method || i.Request.Url != req.URL.String() || !bytes.Equal(i.Request.Body, body) {
+			continue
+		}
+		match = i
+		if !c.replayed[i] {
+			break
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("cassette: no recorded response for %s %s", req.Method, req.URL)
+	}
+	c.replayed[match] = true
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.Response.StatusCode, http.StatusText(match.Response.StatusCode)),
+		StatusCode:    match.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        match.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(match.Response.Body)),
+		ContentLength: int64(len(match.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing
+// in replay mode.
+func (c *Cassette) Save() error {
+	if c.mode != CassetteRecord {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
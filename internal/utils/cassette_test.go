@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, "%s %s %d", r.Method, r.URL.Path, hits)
+	}))
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.Background()
+
+	recorder, err := NewCassette(path, CassetteRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetTransport(recorder)
+	defer SetTransport(nil)
+	var recorded []string
+	for _, cfg := range []FetchConfig{
+		{Url: server.URL + "/a"},
+		{Url: server.URL + "/a"},
+		{Url: server.URL + "/b", Method: "POST", Data: map[string]string{"k": "v"}, Headers: map[string]string{}},
+	} {
+		resp, err := Fetch(ctx, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorded = append(recorded, string(resp.Data))
+	}
+	if err = recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	player, err := NewCassette(path, CassetteReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetTransport(player)
+	for i, cfg := range []FetchConfig{
+		{Url: server.URL + "/a"},
+		{Url: server.URL + "/a"},
+		{Url: server.URL + "/b", Method: "POST", Data: map[string]string{"k": "v"}, Headers: map[string]string{}},
+	} {
+		resp, err := Fetch(ctx, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(resp.Data) != recorded[i] {
+			t.Errorf("request %d: got %q, want %q", i, resp.Data, recorded[i])
+		}
+	}
+	if _, err = Fetch(ctx, FetchConfig{Url: server.URL + "/c"}); err == nil {
+		t.Error("expected an error for a request that was not recorded")
+	}
+}
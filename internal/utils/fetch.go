@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -27,6 +28,7 @@ type FetchConfig struct {
 	Url        string
 	Data       interface{}
 	RetryTimes int
+	Timeout    time.Duration
 	Headers    map[string]string
 	Cookies    map[string]string
 }
@@ -38,12 +40,14 @@ type flareSolverrSolution struct {
 	UserAgent string
 }
 
+// solutions is shared by concurrent fetches, guarded by solutionsMu
 var solutions flareSolverrSolutions
+var solutionsMu sync.RWMutex
 
 func init() {
 	solutions = flareSolverrSolutions{}
 	dataBytes, err := os.ReadFile("solution.json")
-	if err != nil {
+	if err == nil {
 		_ = json.Unmarshal(dataBytes, &solutions)
 	}
 }
@@ -55,11 +59,9 @@ type FetchResponse struct {
 	Cookie     []*http.Cookie
 }
 
-func Fetch(cfg FetchConfig) (*FetchResponse, error) {
-	var req *http.Request
-	var resp *http.Response
+func Fetch(ctx context.Context, cfg FetchConfig) (*FetchResponse, error) {
 	var backoff time.Duration = 1
-	var reqBody io.Reader = nil
+	var reqBody []byte
 	var err error
 
 	if cfg.RetryTimes == 0 {
@@ -68,6 +70,9 @@ func Fetch(cfg FetchConfig) (*FetchResponse, error) {
 	if cfg.Method == "" {
 		cfg.Method = "GET"
 	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
 
 	if cfg.Data != nil && (cfg.Method == "POST" || cfg.Method == "PUT") {
 		if _, exist := cfg.Headers["Content-Type"]; !exist {
@@ -81,126 +86,156 @@ func Fetch(cfg FetchConfig) (*FetchResponse, error) {
 				for k, v := range data {
 					params.Set(k, v)
 				}
-				reqBody = strings.NewReader(params.Encode())
+				reqBody = []byte(params.Encode())
 			case string:
-				reqBody = strings.NewReader(data)
+				reqBody = []byte(data)
 			case url.Values:
-				reqBody = strings.NewReader(data.Encode())
+				reqBody = []byte(data.Encode())
 			default:
 				return nil, errors.New("unsupported data type")
 			}
 		} else if v == "application/json" {
-			var jsonData []byte
-			jsonData, err = json.Marshal(cfg.Data)
+			reqBody, err = json.Marshal(cfg.Data)
 			if err != nil {
 				return nil, err
 			}
-			reqBody = bytes.NewReader(jsonData)
 		} else {
-			reqBody = strings.NewReader(cfg.Data.(string))
+			reqBody = []byte(cfg.Data.(string))
 		}
 	}
 
 	for retryTime := 0; retryTime <= cfg.RetryTimes; retryTime++ {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		req, err = http.NewRequestWithContext(ctx, cfg.Method, cfg.Url, reqBody)
-		if err != nil {
-			return nil, err
+		var res *FetchResponse
+		var retry bool
+		res, retry, err = fetchOnce(ctx, cfg, reqBody)
+		if err == nil {
+			return res, nil
 		}
-		if (cfg.Method == "POST" || cfg.Method == "PUT") {
-			req.Header.Set("Content-Type", "application/json")
+		if !retry {
+			return nil, err
 		}
-		if v, exist := cfg.Headers["User-Agent"]; exist {
-			if v != "" {
-				req.Header.Set("User-Agent", v)
-			}
-		} else {
-			req.Header.Set("User-Agent", userAgent)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff * time.Second):
 		}
-		for k, v := range cfg.Headers {
-			req.Header.Set(k, v)
+		backoff *= 2
+	}
+	return nil, err
+}
+
+func fetchOnce(parent context.Context, cfg FetchConfig, reqBody []byte) (*FetchResponse, bool, error) {
+	parseUrl, err := url.Parse(cfg.Url)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := waitRateLimit(parent, parseUrl.Host); err != nil {
+		return nil, false, err
+	}
+	ctx, cancel := context.WithTimeout(parent, cfg.Timeout)
+	defer cancel()
+
+	var body io.Reader
+	if reqBody != nil {
+		body = bytes.NewReader(reqBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, cfg.Method, cfg.Url, body)
+	if err != nil {
+		return nil, false, err
+	}
+	if cfg.Method == "POST" || cfg.Method == "PUT" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if v, exist := cfg.Headers["User-Agent"]; exist {
+		if v != "" {
+			req.Header.Set("User-Agent", v)
 		}
-		for k, v := range cfg.Cookies {
+	} else {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range cfg.Cookies {
+		req.AddCookie(&http.Cookie{Name: k, Value: v})
+	}
+	// flarecloud?
+	solutionsMu.RLock()
+	v, exist := solutions[fmt.Sprintf("%s://%s", parseUrl.Scheme, parseUrl.Host)]
+	solutionsMu.RUnlock()
+	if exist {
+		req.Header.Set("User-Agent", v.UserAgent)
+		for k, v := range v.Cookies {
 			req.AddCookie(&http.Cookie{Name: k, Value: v})
 		}
-		// flarecloud?
-		parseUrl, _ := url.Parse(cfg.Url)
-		if v, exist := solutions[fmt.Sprintf("%s://%s", parseUrl.Scheme, parseUrl.Host)]; exist {
-			req.Header.Set("User-Agent", v.UserAgent)
-			for k, v := range v.Cookies {
-				req.AddCookie(&http.Cookie{Name: k, Value: v})
-			}
-		}
-		resp, err = http.DefaultClient.Do(req)
-		if err != nil {
-			if isRetryableError(err) {
-				err = errors.New("request error: " + err.Error())
-				time.Sleep(backoff * time.Second)
-				backoff *= 2
-				continue
-			}
+	}
+	resp, err := httpClient().Do(req)
+	if err != nil {
+		if parent.Err() != nil {
+			return nil, false, parent.Err()
 		}
-
-		if resp == nil {
-			return nil, errors.New("response is nil")
+		if isRetryableError(err) {
+			return nil, true, errors.New("request error: " + err.Error())
 		}
+		return nil, false, err
+	}
+	defer resp.Body.Close()
 
-		if isRetryableStatusCode(resp.StatusCode) {
-			err = errors.New("response status code: " + resp.Status)
-			time.Sleep(backoff * time.Second)
-			backoff *= 2
-			continue
-		}
+	if isRetryableStatusCode(resp.StatusCode) {
+		return nil, true, errors.New("response status code: " + resp.Status)
+	}
 
-		if config.Config.FlareSolverrAvaliable &&
-			resp.StatusCode == http.StatusForbidden &&
-			resp.Header.Get("cf-ray") != "" {
-			u, solution, err := FlareSolverr(cfg.Url)
-			if err != nil {
-				return nil, err
-			}
-			solutions[u] = solution
-			cfg.Headers["User-Agent"] = solution.UserAgent
-			cfg.Cookies = solution.Cookies
-			SaveSolutions()
-			return Fetch(cfg)
-		}
-		contentType := resp.Header.Get("Content-Type")
-		var reader io.Reader
-		if strings.Contains(contentType, "charset=") {
-			reader, err = charset.NewReader(resp.Body, contentType)
-		} else {
-			reader = resp.Body
-		}
+	if config.Config.FlareSolverrAvaliable &&
+		resp.StatusCode == http.StatusForbidden &&
+		resp.Header.Get("cf-ray") != "" {
+		u, solution, err := FlareSolverr(parent, cfg.Url)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		dataBytes, err := io.ReadAll(reader)
-		if err != nil {
-			return nil, err
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			log.Logger.Warn(
-				"Fetch error", 
-				zap.String("url", cfg.Url), 
-				zap.Int("status_code", resp.StatusCode),
-				zap.String("data", string(dataBytes)),
-			)
+		solutionsMu.Lock()
+		solutions[u] = solution
+		solutionsMu.Unlock()
+		if cfg.Headers == nil {
+			cfg.Headers = map[string]string{}
 		}
+		cfg.Headers["User-Agent"] = solution.UserAgent
+		cfg.Cookies = solution.Cookies
+		SaveSolutions()
+		res, err := Fetch(parent, cfg)
+		return res, false, err
+	}
+	contentType := resp.Header.Get("Content-Type")
+	var reader io.Reader
+	if strings.Contains(contentType, "charset=") {
+		reader, err = charset.NewReader(resp.Body, contentType)
+	} else {
+		reader = resp.Body
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	dataBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, false, err
+	}
 
-		res := &FetchResponse{
-			StatusCode: resp.StatusCode,
-			Header:     resp.Header,
-			Cookie:     resp.Cookies(),
-			Data:       dataBytes,
-		}
+	if resp.StatusCode != http.StatusOK {
+		log.Logger.Warn(
+			"Fetch error",
+			zap.String("url", cfg.Url),
+			zap.Int("status_code", resp.StatusCode),
+			zap.String("data", string(dataBytes)),
+		)
+	}
 
-		return res, nil
+	res := &FetchResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Cookie:     resp.Cookies(),
+		Data:       dataBytes,
 	}
-	return nil, err
+
+	return res, false, nil
 }
 
 func isRetryableStatusCode(statusCode int) bool {
@@ -260,9 +295,9 @@ type flaresolverrResponse struct {
 	Version        string `json:"version"`
 }
 
-func FlareSolverr(Url string) (string, *flareSolverrSolution, error) {
+func FlareSolverr(ctx context.Context, Url string) (string, *flareSolverrSolution, error) {
 	parseUrl, _ := url.Parse(Url)
-	resp, err := Fetch(FetchConfig{
+	resp, err := Fetch(ctx, FetchConfig{
 		Url: config.Config.FlareSolverr.Url,
 		Data: flaresolverrResquest{
 			URL:        fmt.Sprintf("%s://%s", parseUrl.Scheme, parseUrl.Host),
@@ -280,7 +315,7 @@ func FlareSolverr(Url string) (string, *flareSolverrSolution, error) {
 	if fresp.Status != "ok" {
 		return "", nil, errors.New(fresp.Message)
 	}
-	res := &flareSolverrSolution{}
+	res := &flareSolverrSolution{Cookies: map[string]string{}}
 	for _, cookie := range fresp.Solution.Cookies {
 		res.Cookies[cookie.Name] = cookie.Value
 	}
@@ -289,7 +324,9 @@ func FlareSolverr(Url string) (string, *flareSolverrSolution, error) {
 }
 
 func SaveSolutions() {
+	solutionsMu.RLock()
 	data, err := json.Marshal(solutions)
+	solutionsMu.RUnlock()
 	if err != nil {
 		return
 	}
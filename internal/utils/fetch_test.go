@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFetchInvalidURL(t *testing.T) {
+	for _, url := range []string{"https://exa mple.com/game", "http://[::1/game", "%zz"} {
+		if _, err := Fetch(context.Background(), FetchConfig{Url: url}); err == nil {
+			t.Errorf("Fetch(%q) succeeded, want an error", url)
+		}
+	}
+}
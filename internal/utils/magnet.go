@@ -1,8 +1,14 @@
 package utils
 
 import (
+	"GameDB/internal/model"
 	"bytes"
+	"errors"
 	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/anacrolix/torrent/metainfo"
 )
@@ -27,6 +33,107 @@ func ConvertTorrentToMagnet(torrent []byte) (string, string, error) {
 	return magnet.String(), FormatSize(size), nil
 }
 
+// GetTorrentFiles lists the files of a torrent without padding files. Paths
+// start with the torrent name.
+func GetTorrentFiles(torrent []byte) ([]model.TorrentFile, error) {
+	minfo, err := metainfo.Load(bytes.NewReader(torrent))
+	if err != nil {
+		return nil, err
+	}
+	info, err := minfo.UnmarshalInfo()
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []model.TorrentFile{{Path: info.BestName(), Length: info.Length}}, nil
+	}
+	files := make([]model.TorrentFile, 0, len(info.Files))
+	for _, file := range info.Files {
+		p := strings.Join(file.BestPath(), "/")
+		if strings.HasPrefix(p, ".pad/") || strings.Contains(p, "_____padding_file_") {
+			continue
+		}
+		files = append(files, model.TorrentFile{
+			Path:   path.Join(info.BestName(), p),
+			Length: file.Length,
+		})
+	}
+	return files, nil
+}
+
+type MagnetInfo struct {
+	InfoHash    string
+	DisplayName string
+	Trackers    []string
+}
+
+// ParseMagnet parses a BitTorrent v1 magnet link, the info-hash is returned
+// as lower case hex.
+func ParseMagnet(magnet string) (*MagnetInfo, error) {
+	m, err := metainfo.ParseMagnetUri(magnet)
+	if err != nil {
+		return nil, err
+	}
+	return &MagnetInfo{
+		InfoHash:    m.InfoHash.HexString(),
+		DisplayName: m.DisplayName,
+		Trackers:    m.Trackers,
+	}, nil
+}
+
+var (
+	sizeRegex        = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)*)\s*([KMGT]?)(?:i?B|Bytes?)\b`)
+	russianSizeUnits = strings.NewReplacer("ТБ", "TB", "ГБ", "GB", "МБ", "MB", "КБ", "KB", "Гб", "GB", "Мб", "MB")
+)
+
+// ParseSize parses the first size like "1.5 GB", "700MB" or "100 Bytes" in a
+// string as bytes, units are powers of 1024 like in FormatSize.
+func ParseSize(size string) (int64, error) {
+	res := sizeRegex.FindStringSubmatch(russianSizeUnits.Replace(size))
+	if res == nil {
+		return 0, errors.New("invalid size: " + size)
+	}
+	num, err := strconv.ParseFloat(sizeNumber(res[1]), 64)
+	if err != nil {
+		return 0, err
+	}
+	unit := 0
+	if res[2] != "" {
+		unit = strings.Index("KMGT", strings.ToUpper(res[2])) + 1
+	}
+	for i := 0; i < unit; i++ {
+		num *= 1024
+	}
+	return int64(num), nil
+}
+
+// sizeNumber converts a number with thousands separators and a decimal point
+// or comma, like "1,234.5" or "1,5", to a number ParseFloat accepts. A lone
+// dot is a decimal point, a lone comma followed by three digits is read as a
+// thousands separator.
+func sizeNumber(num string) string {
+	dot, comma := strings.LastIndex(num, "."), strings.LastIndex(num, ",")
+	decimal := -1
+	switch {
+	case dot >= 0 && comma >= 0:
+		decimal = max(dot, comma)
+	case dot >= 0 && strings.Count(num, ".") == 1:
+		decimal = dot
+	case comma >= 0 && strings.Count(num, ",") == 1 && len(num)-comma-1 != 3:
+		decimal = comma
+	}
+	var b strings.Builder
+	for i, r := range num {
+		switch {
+		case i == decimal:
+			b.WriteByte('.')
+		case r != '.' && r != ',':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func FormatSize(size int64) string {
 	const (
 		_        = iota
@@ -0,0 +1,41 @@
+package utils
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	const MB = 1024 * 1024
+	// sizes are computed in floats like ParseSize does
+	gb := func(n float64) int64 { return int64(n * 1024 * 1024 * 1024) }
+	mb := func(n float64) int64 { return int64(n * 1024 * 1024) }
+	cases := []struct {
+		size string
+		want int64
+		ok   bool
+	}{
+		// FitGirl and DODI on 1337x
+		{"12.3 GB", gb(12.3), true},
+		{"700 MB", 700 * MB, true},
+		{"1.1 GB", gb(1.1), true},
+		// FreeGOG
+		{"Size: 4.2 GB", gb(4.2), true},
+		// Xatab in Russian, with a decimal comma
+		{"8,5 ГБ", gb(8.5), true},
+		{"700 Мб", 700 * MB, true},
+		// thousands separators
+		{"1,234 MB", 1234 * MB, true},
+		{"1,234.5 MB", mb(1234.5), true},
+		{"1.234,5 MB", mb(1234.5), true},
+		{"1,234,567 Bytes", 1234567, true},
+		{"100 Bytes", 100, true},
+		{"2.5GiB", gb(2.5), true},
+		// OnlineFix has no size, the files are summed instead
+		{"0", 0, false},
+		{"", 0, false},
+	}
+	for _, c := range cases {
+		size, err := ParseSize(c.size)
+		if (err == nil) != c.ok || size != c.want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", c.size, size, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	matchRomanNumerals = map[string]string{
+		"ii": "2", "iii": "3", "iv": "4", "vi": "6", "vii": "7", "viii": "8",
+	}
+	matchStopWords = map[string]bool{
+		"the": true, "a": true, "an": true, "of": true, "and": true,
+	}
+	// tokens of names of editions, add-ons and other products than the base
+	// game
+	matchEditionWords = map[string]bool{
+		"edition": true, "deluxe": true, "ultimate": true, "goty": true,
+		"definitive": true, "complete": true, "gold": true, "premium": true,
+		"remastered": true, "remake": true, "collection": true, "bundle": true,
+		"pack": true, "dlc": true, "soundtrack": true, "ost": true,
+		"demo": true, "season": true, "pass": true, "upgrade": true,
+		"expansion": true, "artbook": true, "trilogy": true,
+	}
+	releaseYearRegex = regexp.MustCompile(`[(\[]((?:19|20)\d{2})[)\]]`)
+)
+
+const (
+	matchYearBonus       = 0.1
+	matchNearYearBonus   = 0.05
+	matchYearPenalty     = 0.1
+	matchEditionPenalty  = 0.1
+	matchEditionMaxCount = 3
+	matchNumberPenalty   = 0.25
+)
+
+// MatchTokens splits a name into lower case words, "&" is read as "and" and
+// roman numerals as digits.
+func MatchTokens(name string) []string {
+	name = strings.ReplaceAll(strings.ToLower(name), "&", " and ")
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if n, ok := matchRomanNumerals[field]; ok {
+			field = n
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// MatchScore scores from 0 to 1 how well the name of a search result matches
+// the name of a game. The score combines the overlap of the words of both
+// names with their similarity, adds a bonus if the release years are the same
+// and a penalty if they are not, a penalty for every edition or add-on word
+// like "Soundtrack" or "Deluxe" that only the result has, and a penalty for
+// every number, like the 2 of a sequel, that only one of the names has. A
+// year of 0 is unknown.
+func MatchScore(query string, name string, queryYear int, year int) float64 {
+	queryTokens := MatchTokens(query)
+	nameTokens := MatchTokens(name)
+	score := 0.0
+	if strings.Join(queryTokens, " ") == strings.Join(nameTokens, " ") {
+		score = 1
+	} else {
+		score = 0.7*tokenOverlap(queryTokens, nameTokens) + 0.3*Similarity(query, name)
+	}
+	if queryYear > 0 && year > 0 {
+		switch diff := queryYear - year; {
+		case diff == 0:
+			score += matchYearBonus
+		case diff == 1 || diff == -1:
+			score += matchNearYearBonus
+		default:
+			score -= matchYearPenalty
+		}
+	}
+	inQuery := map[string]bool{}
+	for _, token := range queryTokens {
+		inQuery[token] = true
+	}
+	inName := map[string]bool{}
+	editions := 0
+	for _, token := range nameTokens {
+		inName[token] = true
+		if matchEditionWords[token] && !inQuery[token] && editions < matchEditionMaxCount {
+			editions++
+		}
+	}
+	score -= float64(editions) * matchEditionPenalty
+	numbers := 0
+	for token := range inQuery {
+		if isNumber(token) && !inName[token] {
+			numbers++
+		}
+	}
+	for token := range inName {
+		if isNumber(token) && !inQuery[token] {
+			numbers++
+		}
+	}
+	score -= float64(numbers) * matchNumberPenalty
+	return math.Max(0, math.Min(1, score))
+}
+
+func isNumber(token string) bool {
+	for _, r := range token {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return token != ""
+}
+
+// tokenOverlap is the Dice coefficient of two token lists without stop words.
+func tokenOverlap(a, b []string) float64 {
+	count := func(tokens []string) map[string]int {
+		res := map[string]int{}
+		for _, token := range tokens {
+			if !matchStopWords[token] {
+				res[token]++
+			}
+		}
+		return res
+	}
+	ca, cb := count(a), count(b)
+	total, common := 0, 0
+	for token, n := range ca {
+		total += n
+		if m := cb[token]; m < n {
+			common += m
+		} else {
+			common += n
+		}
+	}
+	for _, n := range cb {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+// ReleaseYear returns the year in parentheses or brackets in a raw name like
+// "Atomic Heart [v 1.2] (2023)", or 0.
+func ReleaseYear(name string) int {
+	res := releaseYearRegex.FindStringSubmatch(name)
+	if res == nil {
+		return 0
+	}
+	year, _ := strconv.Atoi(res[1])
+	return year
+}
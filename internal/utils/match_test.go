@@ -0,0 +1,58 @@
+package utils
+
+import "testing"
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		query, better, worse string
+		queryYear            int
+		betterYear           int
+		worseYear            int
+	}{
+		{"The Witcher 3: Wild Hunt", "The Witcher 3: Wild Hunt", "The Witcher 3: Wild Hunt - Original Soundtrack", 0, 0, 0},
+		{"Hades", "Hades", "Hades II", 0, 0, 0},
+		{"Final Fantasy VII", "Final Fantasy 7", "Final Fantasy XV", 0, 0, 0},
+		{"Prey", "Prey", "Prey", 2017, 2017, 2006},
+		{"Cyberpunk 2077", "Cyberpunk 2077", "Cyberpunk 2077: Phantom Liberty DLC", 0, 0, 0},
+	}
+	for _, test := range tests {
+		better := MatchScore(test.query, test.better, test.queryYear, test.betterYear)
+		worse := MatchScore(test.query, test.worse, test.queryYear, test.worseYear)
+		if better <= worse {
+			t.Errorf("MatchScore(%q): %q (%d) = %.2f, not better than %q (%d) = %.2f",
+				test.query, test.better, test.betterYear, better, test.worse, test.worseYear, worse)
+		}
+		if better < 0.7 {
+			t.Errorf("MatchScore(%q, %q) = %.2f, want >= 0.7", test.query, test.better, better)
+		}
+	}
+	if score := MatchScore("Portal", "Portal", 0, 0); score != 1 {
+		t.Errorf("MatchScore of the same name = %.2f, want 1", score)
+	}
+	if score := MatchScore("Stardew Valley", "Valley of the Dead", 0, 0); score >= 0.7 {
+		t.Errorf("MatchScore of different games = %.2f, want < 0.7", score)
+	}
+	// a sequel number that only one name has is another game
+	for query, name := range map[string]string{
+		"Portal":     "Portal 2",
+		"Dark Souls": "Dark Souls III",
+		"Far Cry":    "Far Cry 3",
+		"Far Cry 3":  "Far Cry",
+	} {
+		if score := MatchScore(query, name, 0, 0); score >= 0.7 {
+			t.Errorf("MatchScore(%q, %q) = %.2f, want < 0.7", query, name, score)
+		}
+	}
+}
+
+func TestReleaseYear(t *testing.T) {
+	for name, want := range map[string]int{
+		"Atomic Heart [v 1.2] (2023) PC": 2023,
+		"Cyberpunk 2077 [v 2.12]":        0,
+		"Fallout [1997]":                 1997,
+	} {
+		if year := ReleaseYear(name); year != want {
+			t.Errorf("ReleaseYear(%q) = %d, want %d", name, year, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sync"
+	"time"
+)
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+var (
+	hostLimiters   = map[string]*tokenBucket{}
+	hostLimitersMu sync.RWMutex
+)
+
+// SetRateLimit limits requests made by Fetch to the host of rawURL to rate
+// requests per second with bursts of up to burst requests. A rate <= 0
+// removes the limit.
+func SetRateLimit(rawURL string, rate float64, burst int) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return
+	}
+	hostLimitersMu.Lock()
+	defer hostLimitersMu.Unlock()
+	if rate <= 0 {
+		delete(hostLimiters, u.Host)
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	hostLimiters[u.Host] = &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func waitRateLimit(ctx context.Context, host string) error {
+	hostLimitersMu.RLock()
+	b, exist := hostLimiters[host]
+	hostLimitersMu.RUnlock()
+	if !exist {
+		return nil
+	}
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait for it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"GameDB/internal/model"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	releaseVersionRegex   = regexp.MustCompile(`(?i)\b(?:v|ver\.?|version)[\s.]?(\d+(?:[._]\d+)*[a-z]?)\b`)
+	releaseBuildRegex     = regexp.MustCompile(`(?i)\bbuild[\s._]?(\d+(?:\.\d+)*)\b`)
+	releaseDLCRegex       = regexp.MustCompile(`(?i)\b(\d+)[\s.]*DLCs?\b`)
+	releaseLanguagesRegex = regexp.MustCompile(`(?i)\bMULTi[\s._-]?(\d+)\b`)
+	releasePortableRegex  = regexp.MustCompile(`(?i)\bportable\b`)
+	releaseUpdateRegexps  = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:update|patch)[\s._-]*only\b`),
+		regexp.MustCompile(`(?i)\bonly[\s._-]*(?:update|patch)\b`),
+		regexp.MustCompile(`(?i)\bupdate[\s._]v?\d+(?:\.\d+)*[\s._]to[\s._]v?\d`),
+		regexp.MustCompile(`(?i)\.update\.v?\d+(?:\.\d+)*-\w+$`),
+	}
+	// editions that may be part of a title are only matched when followed by
+	// "Edition"
+	releaseEditionRegex = regexp.MustCompile(`(?i)\b(game[\s.]of[\s.]the[\s.]year|goty|digital[\s.]deluxe|deluxe|ultimate|definitive|collector'?s|enhanced|anniversary|legendary|director'?s[\s.]cut|(?:gold|complete|premium|special|platinum|standard)[\s.]edition)\b`)
+	releaseEditionNames = map[string]string{
+		"game of the year": "Game of the Year",
+		"goty":             "Game of the Year",
+		"digital deluxe":   "Digital Deluxe",
+		"deluxe":           "Deluxe",
+		"ultimate":         "Ultimate",
+		"definitive":       "Definitive",
+		"collectors":       "Collector's",
+		"collector's":      "Collector's",
+		"enhanced":         "Enhanced",
+		"anniversary":      "Anniversary",
+		"legendary":        "Legendary",
+		"director's cut":   "Director's Cut",
+		"directors cut":    "Director's Cut",
+		"gold edition":     "Gold",
+		"complete edition": "Complete",
+		"premium edition":  "Premium",
+		"special edition":  "Special",
+		"platinum edition": "Platinum",
+		"standard edition": "Standard",
+	}
+)
+
+// ParseRelease extracts the version, build, DLC count, edition, language count
+// and portable/update-only markers from a raw release name like
+// "Game.v1.2.3.MULTi10-KaOs" or "Game - Deluxe Edition, Build 1234 + 5 DLCs".
+// Returns nil if nothing is found.
+func ParseRelease(name string) *model.ReleaseInfo {
+	info := &model.ReleaseInfo{}
+	if res := releaseVersionRegex.FindStringSubmatch(name); res != nil {
+		info.Version = strings.ReplaceAll(res[1], "_", ".")
+	}
+	if res := releaseBuildRegex.FindStringSubmatch(name); res != nil {
+		info.Build = res[1]
+	}
+	if res := releaseDLCRegex.FindStringSubmatch(name); res != nil {
+		info.DLCs, _ = strconv.Atoi(res[1])
+	}
+	if res := releaseLanguagesRegex.FindStringSubmatch(name); res != nil {
+		info.Languages, _ = strconv.Atoi(res[1])
+	}
+	if res := releaseEditionRegex.FindStringSubmatch(name); res != nil {
+		key := strings.ToLower(strings.ReplaceAll(res[1], ".", " "))
+		info.Edition = releaseEditionNames[key]
+	}
+	info.Portable = releasePortableRegex.MatchString(name)
+	for _, re := range releaseUpdateRegexps {
+		if re.MatchString(name) {
+			info.UpdateOnly = true
+			break
+		}
+	}
+	if *info == (model.ReleaseInfo{}) {
+		return nil
+	}
+	return info
+}
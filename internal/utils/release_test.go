@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"GameDB/internal/model"
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		want *model.ReleaseInfo
+	}{
+		{"Game.v1.2.3.MULTi10-KaOs", &model.ReleaseInfo{Version: "1.2.3", Languages: 10}},
+		{"Game - [DODI Repack]", nil},
+		{"Game: Deluxe Edition, Build 1234 + 5 DLCs", &model.ReleaseInfo{Build: "1234", DLCs: 5, Edition: "Deluxe"}},
+		{"Game GOTY Edition v2.0a Portable", &model.ReleaseInfo{Version: "2.0a", Edition: "Game of the Year", Portable: true}},
+		{"Game.Update.v1.05-CODEX", &model.ReleaseInfo{Version: "1.05", UpdateOnly: true}},
+		{"Game Update v1.0 to v1.1", &model.ReleaseInfo{Version: "1.0", UpdateOnly: true}},
+		{"Complete Game Collection", nil},
+		{"Game: Complete Edition (Update Only)", &model.ReleaseInfo{Edition: "Complete", UpdateOnly: true}},
+	}
+	for _, test := range tests {
+		got := ParseRelease(test.name)
+		if (got == nil) != (test.want == nil) || got != nil && *got != *test.want {
+			t.Errorf("ParseRelease(%q) = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
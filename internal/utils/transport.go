@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"net/http"
+	"sync"
+)
+
+var (
+	transport   http.RoundTripper = http.DefaultTransport
+	transportMu sync.RWMutex
+)
+
+// SetTransport replaces the transport used by Fetch, e.g. with a Cassette.
+// A nil transport restores http.DefaultTransport.
+func SetTransport(rt http.RoundTripper) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	transport = rt
+}
+
+func httpClient() *http.Client {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return &http.Client{Transport: transport}
+}
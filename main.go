@@ -4,8 +4,13 @@ import (
 	"GameDB/internal/cache"
 	"GameDB/internal/cmd"
 	"GameDB/internal/config"
+	"GameDB/internal/crawler"
 	"GameDB/internal/db"
 	"GameDB/internal/log"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"go.uber.org/zap"
 )
@@ -17,7 +22,27 @@ func main() {
 	if config.Config.RedisAvaliable {
 		cache.InitRedis()
 	}
-	if err := cmd.RootCmd.Execute(); err != nil {
+	if err := crawler.LoadScrapers(config.Config.ScrapersDir); err != nil {
+		log.Logger.Error("Failed to load scrapers", zap.Error(err))
+	}
+	cmd.RefreshSourceUsages()
+	if _, err := crawler.LoadFormatterRules(config.Config.RulesDir); err != nil {
+		log.Logger.Error("Failed to load formatter rules", zap.Error(err))
+	}
+	go reloadFormatterRulesOnHangup()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := cmd.RootCmd.ExecuteContext(ctx); err != nil {
 		log.Logger.Error("main", zap.Error(err))
 	}
 }
+
+func reloadFormatterRulesOnHangup() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		if _, err := crawler.LoadFormatterRules(config.Config.RulesDir); err != nil {
+			log.Logger.Error("Failed to reload formatter rules", zap.Error(err))
+		}
+	}
+}